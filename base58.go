@@ -0,0 +1,125 @@
+package randchars
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidBase58Check is returned when a string isn't valid Base58Check.
+var ErrInvalidBase58Check = errors.New("randchars: invalid Base58Check")
+
+// Base58CheckSize is the size, in bytes, of the Base58Check checksum: the
+// first 4 bytes of the double SHA-256 of the payload.
+const Base58CheckSize = 4
+
+// base58Check returns the checksum of payload.
+func base58Check(payload []byte) [Base58CheckSize]byte {
+	h := sha256.Sum256(payload)
+	h = sha256.Sum256(h[:])
+	return [Base58CheckSize]byte(h[:Base58CheckSize])
+}
+
+// appendBase58 appends the Bitcoin Base58 encoding of b to dst and returns
+// the extended slice. Each leading zero byte of b is encoded as a '1'.
+func appendBase58(dst, b []byte) []byte {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+	// digits are the base 58 digits, least significant first; log(256) /
+	// log(58) is less than 1.37.
+	digits := make([]byte, 0, (len(b)-zeros)*137/100+1)
+	for _, v := range b[zeros:] {
+		carry := int(v)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+	for i := 0; i < zeros; i++ {
+		dst = append(dst, base58[0])
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		dst = append(dst, base58[digits[i]])
+	}
+	return dst
+}
+
+// decodeBase58 returns the bytes encoded by the Bitcoin Base58 string s.
+func decodeBase58(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58[0] {
+		zeros++
+	}
+	// decoded are the decoded bytes, least significant first
+	var decoded []byte
+	for i := zeros; i < len(s); i++ {
+		carry := strings.IndexByte(base58, s[i])
+		if carry < 0 {
+			return nil, fmt.Errorf("%w: %q: not a Base58 character", ErrInvalidBase58Check, s[i])
+		}
+		for j := range decoded {
+			carry += int(decoded[j]) * 58
+			decoded[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			decoded = append(decoded, byte(carry))
+			carry >>= 8
+		}
+	}
+	b := make([]byte, zeros, zeros+len(decoded))
+	for i := len(decoded) - 1; i >= 0; i-- {
+		b = append(b, decoded[i])
+	}
+	return b, nil
+}
+
+// EncodeBase58Check returns the Base58Check encoding of payload: the Bitcoin
+// Base58 encoding of payload followed by its checksum, the first 4 bytes of
+// its double SHA-256. Unlike Bitcoin addresses, there is no version byte; it
+// can be included in payload.
+func EncodeBase58Check(payload []byte) []byte {
+	b := make([]byte, 0, len(payload)+Base58CheckSize)
+	b = append(b, payload...)
+	sum := base58Check(payload)
+	b = append(b, sum[:]...)
+	return appendBase58(nil, b)
+}
+
+// ValidateBase58Check validates s, a Base58Check string as generated by
+// Base58Check or EncodeBase58Check, and returns its payload. An error
+// wrapping ErrInvalidBase58Check is returned if s isn't Bitcoin Base58 or its
+// checksum doesn't match.
+func ValidateBase58Check(s string) ([]byte, error) {
+	b, err := decodeBase58(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < Base58CheckSize {
+		return nil, fmt.Errorf("%w: %q: too short", ErrInvalidBase58Check, s)
+	}
+	payload := b[:len(b)-Base58CheckSize]
+	if base58Check(payload) != [Base58CheckSize]byte(b[len(payload):]) {
+		return nil, fmt.Errorf("%w: %q: checksum mismatch", ErrInvalidBase58Check, s)
+	}
+	return payload, nil
+}
+
+// Base58Check returns the Base58Check encoding, see EncodeBase58Check, of a
+// payload of n random bytes from g's Source. This will panic if n < 0.
+func (g *Generator) Base58Check(n int) []byte {
+	if n < 0 {
+		panic(fmt.Sprintf("%d: value out of bounds", n))
+	}
+	payload := make([]byte, n)
+	fill(g.src, payload)
+	return EncodeBase58Check(payload)
+}
@@ -0,0 +1,92 @@
+package randchars
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		b        string
+		expected string
+	}{
+		{"", ""},
+		{"00", "1"},
+		{"0000287fb4cd", "11233QC4"},
+		{hex.EncodeToString([]byte("Hello World!")), "2NEpo7TZRRrLZSi2U"},
+		{"ff", "5Q"},
+	}
+	for _, test := range tests {
+		b, _ := hex.DecodeString(test.b)
+		s := string(appendBase58(nil, b))
+		if s != test.expected {
+			t.Errorf("%s: got %q; want %q", test.b, s, test.expected)
+		}
+		d, err := decodeBase58(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", s, err)
+			continue
+		}
+		if !bytes.Equal(d, b) {
+			t.Errorf("%q: got %x; want %x", s, d, b)
+		}
+	}
+}
+
+func TestBase58Check(t *testing.T) {
+	// a Bitcoin address: the version byte, 0, followed by a public key hash
+	payload, _ := hex.DecodeString("00010966776006953d5567439e5e39f86a0d273bee")
+	s := string(EncodeBase58Check(payload))
+	if s != "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM" {
+		t.Errorf("got %q; want %q", s, "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM")
+	}
+	p, err := ValidateBase58Check(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(p, payload) {
+		t.Errorf("got %x; want %x", p, payload)
+	}
+	tests := []struct {
+		s   string
+		err string
+	}{
+		{"16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN", "randchars: invalid Base58Check: \"16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN\": checksum mismatch"},
+		{"0OIl", "randchars: invalid Base58Check: '0': not a Base58 character"},
+		{"", "randchars: invalid Base58Check: \"\": too short"},
+		{"2g", "randchars: invalid Base58Check: \"2g\": too short"},
+	}
+	for _, test := range tests {
+		_, err := ValidateBase58Check(test.s)
+		if err == nil {
+			t.Errorf("%q: got no error; want %q", test.s, test.err)
+			continue
+		}
+		if err.Error() != test.err {
+			t.Errorf("%q: got %q; want %q", test.s, err, test.err)
+		}
+		if !errors.Is(err, ErrInvalidBase58Check) {
+			t.Errorf("%q: got %v; want %v", test.s, err, ErrInvalidBase58Check)
+		}
+	}
+	g := NewGeneratorWithSeed(0)
+	for _, n := range []int{0, 1, 16, 32} {
+		for _, b := range [][]byte{g.Base58Check(n), Base58Check(n)} {
+			p, err := ValidateBase58Check(string(b))
+			if err != nil {
+				t.Errorf("%q: unexpected error: %s", b, err)
+				continue
+			}
+			if len(p) != n {
+				t.Errorf("%q: got a %d byte payload; want %d", b, len(p), n)
+			}
+			for _, c := range b {
+				if !Base58Charset.Contains(c) {
+					t.Errorf("%q: %q is not in %q", b, c, Base58Charset)
+				}
+			}
+		}
+	}
+}
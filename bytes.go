@@ -0,0 +1,71 @@
+package randchars
+
+import "encoding/binary"
+
+// fill fills p with bytes from src, 8 bytes, little-endian, per Uint64. The
+// unused bytes of the last value are discarded.
+func fill(src Source, p []byte) {
+	for len(p) >= 8 {
+		binary.LittleEndian.PutUint64(p, src.Uint64())
+		p = p[8:]
+	}
+	if len(p) > 0 {
+		v := src.Uint64()
+		for i := range p {
+			p[i] = byte(v)
+			v >>= 8
+		}
+	}
+}
+
+// Read fills p with random bytes from g's Source; it implements io.Reader.
+// It always returns len(p), nil. Each 8 bytes use one Uint64 and the unused
+// bytes of a partial Uint64 are discarded, so the output of a seeded
+// Generator depends on the lengths of the reads.
+func (g *Generator) Read(p []byte) (n int, err error) {
+	fill(g.src, p)
+	return len(p), nil
+}
+
+// Uint64 returns a random uint64 from g's Source. It implements
+// math/rand/v2's Source, so a Generator can be used with rand.New.
+func (g *Generator) Uint64() uint64 {
+	return g.src.Uint64()
+}
+
+// Uint32 returns a random uint32 from g's Source.
+func (g *Generator) Uint32() uint32 {
+	return g.src.Uint32()
+}
+
+// Read fills p with random bytes; see Generator.Read.
+func (g *Base64Generator) Read(p []byte) (n int, err error) {
+	fill(g.src, p)
+	return len(p), nil
+}
+
+// Uint64 returns a random uint64; see Generator.Uint64.
+func (g *Base64Generator) Uint64() uint64 {
+	return g.src.Uint64()
+}
+
+// Uint32 returns a random uint32.
+func (g *Base64Generator) Uint32() uint32 {
+	return g.src.Uint32()
+}
+
+// Read fills p with random bytes; see Generator.Read.
+func (g *Base64URLGenerator) Read(p []byte) (n int, err error) {
+	fill(g.src, p)
+	return len(p), nil
+}
+
+// Uint64 returns a random uint64; see Generator.Uint64.
+func (g *Base64URLGenerator) Uint64() uint64 {
+	return g.src.Uint64()
+}
+
+// Uint32 returns a random uint32.
+func (g *Base64URLGenerator) Uint32() uint32 {
+	return g.src.Uint32()
+}
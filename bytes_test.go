@@ -0,0 +1,168 @@
+package randchars
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"io"
+	mrand "math/rand"
+	"math/rand/v2"
+	"testing"
+)
+
+// the generators can be used as an io.Reader and as a math/rand/v2 Source.
+var (
+	_ io.Reader   = (*Generator)(nil)
+	_ io.Reader   = (*Base64Generator)(nil)
+	_ io.Reader   = (*Base64URLGenerator)(nil)
+	_ rand.Source = (*Generator)(nil)
+	_ rand.Source = (*Base64Generator)(nil)
+	_ rand.Source = (*Base64URLGenerator)(nil)
+)
+
+func TestRead(t *testing.T) {
+	for _, test := range sources(0) {
+		for _, n := range []int{0, 1, 7, 8, 9, 16, 1000} {
+			g := NewGeneratorWithSource(test.src)
+			g.Seed(42)
+			p := make([]byte, n)
+			got, err := g.Read(p)
+			if got != n || err != nil {
+				t.Errorf("%s: %d: got %d, %v; want %d, nil", test.name, n, got, err, n)
+			}
+			// the bytes are the Source's uint64s in little-endian order
+			g.Seed(42)
+			var expected []byte
+			for len(expected) < n {
+				expected = binary.LittleEndian.AppendUint64(expected, g.Uint64())
+			}
+			if string(p) != string(expected[:n]) {
+				t.Errorf("%s: %d: got %x; want %x", test.name, n, p, expected[:n])
+			}
+		}
+	}
+	// a chi-square test of the byte values; the critical value is for
+	// p = 0.001 with 255 degrees of freedom.
+	p := make([]byte, 256*1000)
+	NewGeneratorWithSeed(0).Read(p)
+	var counts [256]int
+	for _, b := range p {
+		counts[b]++
+	}
+	var chi float64
+	for _, c := range counts {
+		d := float64(c - 1000)
+		chi += d * d / 1000
+	}
+	if chi > 330.52 {
+		t.Errorf("chi-square %.2f exceeds 330.52", chi)
+	}
+}
+
+func TestReadBase64Generators(t *testing.T) {
+	a, b := NewBase64GeneratorWithSeed(42), NewBase64URLGeneratorWithSeed(42)
+	x, y := make([]byte, 100), make([]byte, 100)
+	io.ReadFull(a, x)
+	io.ReadFull(b, y)
+	if string(x) != string(y) {
+		t.Errorf("got %x and %x from the same seed", x, y)
+	}
+	a.Seed(42)
+	if v := a.Uint64(); v != binary.LittleEndian.Uint64(x) {
+		t.Errorf("Uint64: got %x; want %x", v, binary.LittleEndian.Uint64(x))
+	}
+	b.Seed(42)
+	if v, w := b.Uint32(), NewXoroshiroSource(42).Uint32(); v != w {
+		t.Errorf("Uint32: got %x; want %x", v, w)
+	}
+}
+
+func TestMathRandSource(t *testing.T) {
+	r1, r2 := rand.New(NewGeneratorWithSeed(42)), rand.New(NewBase64GeneratorWithSeed(42))
+	g1, g2 := NewGeneratorWithSeed(42), NewBase64GeneratorWithSeed(42)
+	for i := 0; i < 100; i++ {
+		if v, w := r1.Uint64(), g1.Uint64(); v != w {
+			t.Fatalf("%d: got %x; want %x", i, v, w)
+		}
+		if v, w := r2.Uint64(), g2.Uint64(); v != w {
+			t.Fatalf("%d: got %x; want %x", i, v, w)
+		}
+	}
+	if v := r1.IntN(10); v < 0 || v >= 10 {
+		t.Errorf("IntN: got %d; want [0, 10)", v)
+	}
+}
+
+func BenchmarkRead_32(b *testing.B) {
+	g := NewGenerator()
+	p := make([]byte, 32)
+	b.SetBytes(32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Read(p)
+	}
+}
+
+func BenchmarkReadBase64Generator_32(b *testing.B) {
+	g := NewBase64Generator()
+	p := make([]byte, 32)
+	b.SetBytes(32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Read(p)
+	}
+}
+
+func BenchmarkReadMathRand_32(b *testing.B) {
+	r := mrand.New(mrand.NewSource(0))
+	p := make([]byte, 32)
+	b.SetBytes(32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Read(p)
+	}
+}
+
+func BenchmarkReadCryptoRand_32(b *testing.B) {
+	p := make([]byte, 32)
+	b.SetBytes(32)
+	for i := 0; i < b.N; i++ {
+		crand.Read(p)
+	}
+}
+
+func BenchmarkUint64(b *testing.B) {
+	g := NewGenerator()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Uint64()
+	}
+}
+
+func BenchmarkUint64Base64Generator(b *testing.B) {
+	g := NewBase64Generator()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Uint64()
+	}
+}
+
+func BenchmarkUint64MathRand(b *testing.B) {
+	r := mrand.New(mrand.NewSource(0))
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
+
+func BenchmarkUint64MathRandPCG(b *testing.B) {
+	r := rand.NewPCG(0, 0)
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
+
+func BenchmarkUint64MathRandChaCha8(b *testing.B) {
+	r := rand.NewChaCha8([32]byte{})
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
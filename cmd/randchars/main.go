@@ -17,6 +17,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/mohae/randchars"
 	"github.com/mohae/randchars/crandchars"
+	"github.com/mohae/randchars/passphrase"
 )
 
 var (
@@ -31,14 +33,34 @@ var (
 	c     bool
 	out   = "stdout"
 	chars = "base64"
+	tmpl  string
+	bits  float64
 	help  bool
+	// noLeadingZero forbids a leading '0', so numbers are fixed-width
+	noLeadingZero bool
+	// passphrase flags
+	words         int
+	wordlist      = "eff-large"
+	sep           = "-"
+	capitalize    bool
+	injectDigits  int
+	injectSymbols int
 )
 
 func init() {
 	flag.Usage = usage
-	flag.StringVar(&out, "-o", out, "output destination")
-	flag.StringVar(&chars, "chars", chars, "charset: alphanum, alpha, lalphanum, lalpha, ualphanum, ualpha, base64, base64url")
+	flag.StringVar(&out, "o", out, "output destination")
+	flag.StringVar(&chars, "chars", chars, "charset: alphanum, alpha, lalphanum, lalpha, ualphanum, ualpha, base64, base64url, base32, base32hex, crockford, base58, base58flickr, digits, hex, lowerhex, upperhex, z85, ascii85, printable, shell, urlpath, json, xmlattr, dsn")
+	flag.BoolVar(&noLeadingZero, "no-leading-zero", false, "don't start a set with a 0, e.g. for fixed-width numbers with -chars digits")
+	flag.StringVar(&tmpl, "template", "", "generate from a template, e.g. AAA-999-aaa, instead of a charset")
+	flag.Float64Var(&bits, "bits", 0, "generate the minimum number of characters needed for this many bits of entropy")
 	flag.BoolVar(&c, "c", false, "use a CSPRNG")
+	flag.IntVar(&words, "words", 0, "generate passphrases with this many words, using a CSPRNG, instead of characters")
+	flag.StringVar(&wordlist, "wordlist", wordlist, "passphrase wordlist: eff-large, eff-short, or the path of a wordlist file")
+	flag.StringVar(&sep, "sep", sep, "passphrase word separator")
+	flag.BoolVar(&capitalize, "capitalize", false, "capitalize each passphrase word")
+	flag.IntVar(&injectDigits, "inject-digits", 0, "number of digits to inject into each passphrase")
+	flag.IntVar(&injectSymbols, "inject-symbols", 0, "number of symbols to inject into each passphrase")
 	flag.BoolVar(&help, "h", false, "help")
 	flag.BoolVar(&help, "help", false, "help")
 }
@@ -56,14 +78,76 @@ func realMain() int {
 		flag.PrintDefaults()
 		return 0
 	}
+	if words > 0 {
+		return passphrases(flag.Args())
+	}
 
 	args := flag.Args()
+	var t *randchars.Template
+	var err error
+	if tmpl != "" && bits > 0 {
+		fmt.Fprintln(os.Stderr, "error: -bits can't be used with -template")
+		return 1
+	}
+	if tmpl != "" && noLeadingZero {
+		fmt.Fprintln(os.Stderr, "error: -no-leading-zero can't be used with -template")
+		return 1
+	}
+	if tmpl != "" || bits > 0 {
+		// the length of each set is determined by the flag; the only arg is
+		// the number of sets to generate
+		var length int
+		if tmpl != "" {
+			t, err = randchars.CompileTemplate(tmpl)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %s\n", err)
+				return 1
+			}
+			length = t.Len()
+		} else {
+			cs, err := charset(chars)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %s\n", err)
+				return 1
+			}
+			length = cs.MinLength(bits)
+			if length < 0 {
+				fmt.Fprintf(os.Stderr, "error: %s: no length has %.1f bits of entropy\n", chars, bits)
+				return 1
+			}
+			// the first character has fewer choices
+			g := Generator{Charset: cs, NoLeadingZero: noLeadingZero}
+			if g.Entropy(length) < bits {
+				length++
+			}
+		}
+		if len(args) > 1 {
+			flag.Usage()
+			return 1
+		}
+		sets := 1
+		if len(args) == 1 {
+			sets, err = strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+		}
+		if sets < 1 {
+			fmt.Fprintf(os.Stderr, "error: %d: the number of sets must be at least 1\n", sets)
+			flag.Usage()
+			return 1
+		}
+		args = make([]string, sets)
+		for i := range args {
+			args[i] = strconv.Itoa(length)
+		}
+	}
 	if len(args) == 0 {
 		flag.Usage()
 		return 1
 	}
 	l := make([]int, len(args))
-	var err error
 	var n int
 	for i, v := range args {
 		l[i], err = strconv.Atoi(v)
@@ -73,16 +157,12 @@ func realMain() int {
 		}
 		n += l[i]
 	}
-	// open output if not stdout
-	var f *os.File
-	if out == "stdout" {
-		f = os.Stdout
-	} else {
-		f, err = os.OpenFile(out, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0664)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return 1
-		}
+	f, err := output()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if f != os.Stdout {
 		defer f.Close()
 	}
 	g, err := NewGenerator(n, c, chars)
@@ -90,10 +170,15 @@ func realMain() int {
 		fmt.Fprintf(os.Stderr, "error: %s\n", err)
 		return 1
 	}
+	g.Template = t
+	g.NoLeadingZero = noLeadingZero
 
 	n = 0
+	minBits, maxBits := math.Inf(1), 0.0
 	for _, v := range l {
-		b := g.GetChars(v)
+		minBits = math.Min(minBits, g.Entropy(v))
+		maxBits = math.Max(maxBits, g.Entropy(v))
+		b, err := g.Chars(v)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error generating %d random chars: %s\n", v, err)
 			return 1
@@ -110,14 +195,96 @@ func realMain() int {
 			return 1
 		}
 	}
-	fmt.Printf("%d sets totalling %d random characters were generated and written to %s\n", len(l), n, out)
+	entropy := fmt.Sprintf("%.1f", minBits)
+	if maxBits != minBits {
+		entropy += fmt.Sprintf(" to %.1f", maxBits)
+	}
+	fmt.Printf("%d sets totalling %d random characters, with %s bits of entropy each, were generated and written to %s\n", len(l), n, entropy, out)
+	return 0
+}
+
+// passphrases generates the number of passphrases in args, 1 if there are no
+// args.
+func passphrases(args []string) int {
+	if len(args) > 1 {
+		flag.Usage()
+		return 1
+	}
+	n := 1
+	var err error
+	if len(args) == 1 {
+		n, err = strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if n < 1 {
+		fmt.Fprintf(os.Stderr, "error: %d: the number of passphrases must be at least 1\n", n)
+		flag.Usage()
+		return 1
+	}
+	var wl passphrase.Wordlist
+	switch strings.ToLower(wordlist) {
+	case "eff-large":
+		wl = passphrase.EFFLarge
+	case "eff-short":
+		wl = passphrase.EFFShort
+	default:
+		wl, err = passphrase.LoadWordlist(wordlist)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err)
+			return 1
+		}
+	}
+	g, err := passphrase.New(crandchars.New(), passphrase.Policy{
+		Words:      words,
+		Wordlist:   wl,
+		Separator:  sep,
+		Capitalize: capitalize,
+		Digits:     injectDigits,
+		Symbols:    injectSymbols,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+	f, err := output()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if f != os.Stdout {
+		defer f.Close()
+	}
+	for i := 0; i < n; i++ {
+		b := append(g.Generate(), '\n')
+		_, err = f.Write(b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error writing passphrase to %s\n", out)
+			return 1
+		}
+	}
+	fmt.Printf("%d passphrases with %.1f bits of entropy each were generated and written to %s\n", n, g.Entropy(), out)
 	return 0
 }
 
+// output opens the output destination; it is stdout unless the o flag was
+// set.
+func output() (*os.File, error) {
+	if out == "stdout" {
+		return os.Stdout, nil
+	}
+	return os.OpenFile(out, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0664)
+}
+
 // Generator handles the generation of random characters
 type Generator struct {
-	Gen      randchars.Generatorer
-	GetChars func(n int) []byte
+	Gen      randchars.CharsGenerator
+	Charset  randchars.Charset
+	Template *randchars.Template
+	// NoLeadingZero forbids '0' as the first character.
+	NoLeadingZero bool
 }
 
 func NewGenerator(n int, c bool, chars string) (*Generator, error) {
@@ -130,32 +297,128 @@ func NewGenerator(n int, c bool, chars string) (*Generator, error) {
 	} else {
 		g.Gen = randchars.NewGenerator()
 	}
+	var err error
+	g.Charset, err = charset(chars)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// charset returns the Charset for the chars flag value.
+func charset(chars string) (randchars.Charset, error) {
 	switch strings.ToLower(chars) {
 	case "alphanum":
-		g.GetChars = g.Gen.AlphaNum
+		return randchars.AlphaNumCharset, nil
 	case "alpha":
-		g.GetChars = g.Gen.Alpha
-	case "loweralphanum":
-		g.GetChars = g.Gen.LowerAlphaNum
-	case "loweralpha":
-		g.GetChars = g.Gen.LowerAlpha
-	case "upperalphanum":
-		g.GetChars = g.Gen.UpperAlphaNum
-	case "upperalpha":
-		g.GetChars = g.Gen.UpperAlpha
+		return randchars.AlphaCharset, nil
+	case "lalphanum", "loweralphanum":
+		return randchars.LowerAlphaNumCharset, nil
+	case "lalpha", "loweralpha":
+		return randchars.LowerAlphaCharset, nil
+	case "ualphanum", "upperalphanum":
+		return randchars.UpperAlphaNumCharset, nil
+	case "ualpha", "upperalpha":
+		return randchars.UpperAlphaCharset, nil
 	case "base64":
-		g.GetChars = g.Gen.Base64
+		return randchars.Base64Charset, nil
 	case "base64url":
-		g.GetChars = g.Gen.Base64URL
-	default:
-		return nil, fmt.Errorf("%q is not supported", chars)
+		return randchars.Base64URLCharset, nil
+	case "base32":
+		return randchars.Base32Charset, nil
+	case "base32hex":
+		return randchars.Base32HexCharset, nil
+	case "crockford":
+		return randchars.CrockfordCharset, nil
+	case "base58":
+		return randchars.Base58Charset, nil
+	case "base58flickr":
+		return randchars.Base58FlickrCharset, nil
+	case "digits":
+		return randchars.DigitsCharset, nil
+	case "hex", "lowerhex":
+		return randchars.LowerHexCharset, nil
+	case "upperhex":
+		return randchars.UpperHexCharset, nil
+	case "z85":
+		return randchars.Z85Charset, nil
+	case "ascii85":
+		return randchars.Ascii85Charset, nil
+	case "printable":
+		return randchars.PrintableCharset, nil
+	case "shell":
+		return randchars.ShellSafeCharset, nil
+	case "urlpath":
+		return randchars.URLPathCharset, nil
+	case "json":
+		return randchars.JSONSafeCharset, nil
+	case "xmlattr":
+		return randchars.XMLAttrCharset, nil
+	case "dsn":
+		return randchars.DSNPasswordCharset, nil
 	}
-	return &g, nil
+	return randchars.Charset{}, fmt.Errorf("%q is not supported", chars)
+}
+
+// Chars returns n random characters from the Generator's Charset. An error is
+// returned instead of panicking, e.g. if n < 0 or the CSPRNG fails. If the
+// Generator has a Template, the characters are generated from it and n is
+// ignored.
+func (g *Generator) Chars(n int) ([]byte, error) {
+	if g.Template != nil {
+		return g.Template.GenerateErr(g.Gen)
+	}
+	if !g.NoLeadingZero || n <= 0 {
+		return g.Gen.Generate(g.Charset, n)
+	}
+	first, err := g.nonZero()
+	if err != nil {
+		return nil, err
+	}
+	b, err := g.Gen.Generate(first, 1)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := g.Gen.Generate(g.Charset, n-1)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, rest...), nil
+}
+
+// nonZero returns the Charset for the first character when NoLeadingZero is
+// set: the Generator's Charset without '0'.
+func (g *Generator) nonZero() (randchars.Charset, error) {
+	cs, err := g.Charset.Exclude("0")
+	if err != nil {
+		return cs, fmt.Errorf("%q: no characters other than 0", g.Charset)
+	}
+	return cs, nil
+}
+
+// Entropy returns the entropy, in bits, of n random characters from the
+// Generator's Charset, accounting for NoLeadingZero, or, if it has one, of its
+// Template's characters.
+func (g *Generator) Entropy(n int) float64 {
+	if g.Template != nil {
+		return g.Template.Entropy()
+	}
+	if g.NoLeadingZero && n > 0 {
+		first, err := g.nonZero()
+		if err != nil {
+			return 0
+		}
+		return first.Entropy(1) + g.Charset.Entropy(n-1)
+	}
+	return g.Charset.Entropy(n)
 }
 
 func usage() {
 	fmt.Fprintf(os.Stderr, "Usage of %s:\n", name)
 	fmt.Fprintf(os.Stderr, "    %s <int>...\n", name)
+	fmt.Fprintf(os.Stderr, "    %s -bits <float> [<int>]\n", name)
+	fmt.Fprintf(os.Stderr, "    %s -template <template> [<int>]\n", name)
+	fmt.Fprintf(os.Stderr, "    %s -words <int> [<int>]\n", name)
 	fmt.Fprint(os.Stderr, "\n")
 	fmt.Fprint(os.Stderr, "  help:\n")
 	fmt.Fprintf(os.Stderr, "    %s -h\n", name)
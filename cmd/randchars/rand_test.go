@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"math"
 	"testing"
 
 	"github.com/mohae/randchars"
@@ -15,13 +17,18 @@ func TestRandGen(t *testing.T) {
 	}{
 		{"", 10, "", "\"\" is not supported"},
 		{"alphanum", 0, "", "0: invalid character amount; must be > 0"},
-		{"alphanum", 12, "AMp00A7cpFLj", ""},
-		{"alpha", 12, "WYtuiYrcbnjh", ""},
-		{"loweralphanum", 12, "28tymkzkvf3t", ""},
-		{"loweralpha", 12, "wytuiyrcbnjh", ""},
-		{"upperalphanum", 12, "28TYMKZKVF3T", ""},
-		{"upperalpha", 12, "WYTUIYRCBNJH", ""},
-		{"base64", 12, "iw7GmwTUjnz7", ""},
+		{"alphanum", 12, "3y3UVwvYWUxr", ""},
+		{"alpha", 12, "UylJzlpupvsM", ""},
+		{"loweralphanum", 12, "6gcvtpm9igtf", ""},
+		{"loweralpha", 12, "xgblzefqwazb", ""},
+		{"upperalphanum", 12, "6GCVTPM9IGTF", ""},
+		{"upperalpha", 12, "XGBLZEFQWAZB", ""},
+		{"base64", 12, "iEuWKwugN37o", ""},
+		// the short names in the -chars help
+		{"lalphanum", 12, "6gcvtpm9igtf", ""},
+		{"lalpha", 12, "xgblzefqwazb", ""},
+		{"ualphanum", 12, "6GCVTPM9IGTF", ""},
+		{"ualpha", 12, "XGBLZEFQWAZB", ""},
 	}
 	for _, test := range tests {
 		g, err := NewGenerator(test.n, false, test.chars)
@@ -32,10 +39,96 @@ func TestRandGen(t *testing.T) {
 			continue
 		}
 		g.Gen.(*randchars.Generator).Seed(0)
-		b := g.GetChars(test.n)
+		b, err := g.Chars(test.n)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.chars, err)
+			continue
+		}
 		if string(b) != test.expected {
 			t.Errorf("%s: got %q; want %q", test.chars, string(b), test.expected)
 		}
 
 	}
 }
+
+func TestRandGenNegative(t *testing.T) {
+	g, err := NewGenerator(10, true, "alphanum")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = g.Chars(-1)
+	if !errors.Is(err, randchars.ErrNegativeLength) {
+		t.Errorf("got %v; want %v", err, randchars.ErrNegativeLength)
+	}
+}
+
+func TestRandGenTemplate(t *testing.T) {
+	tmpl := randchars.MustCompileTemplate("AAA-999-aaa")
+	g, err := NewGenerator(tmpl.Len(), false, "base64")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	g.Template = tmpl
+	g.Gen.(*randchars.Generator).Seed(0)
+	b, err := g.Chars(tmpl.Len())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	g.Gen.(*randchars.Generator).Seed(0)
+	expected := tmpl.Generate(g.Gen)
+	if string(b) != string(expected) {
+		t.Errorf("got %q; want %q", string(b), string(expected))
+	}
+}
+
+func TestRandGenEntropy(t *testing.T) {
+	g, err := NewGenerator(10, false, "base64")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if e := g.Entropy(22); e != 132 {
+		t.Errorf("got %f; want 132", e)
+	}
+	g.Template = randchars.MustCompileTemplate("999-999")
+	if e, expected := g.Entropy(7), randchars.DigitsCharset.Entropy(6); e != expected {
+		t.Errorf("template: got %f; want %f", e, expected)
+	}
+}
+
+func TestRandGenNoLeadingZero(t *testing.T) {
+	for _, chars := range []string{"digits", "hex", "upperhex"} {
+		for _, c := range []bool{false, true} {
+			g, err := NewGenerator(10, c, chars)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %s", chars, err)
+			}
+			g.NoLeadingZero = true
+			for i := 0; i < 1000; i++ {
+				b, err := g.Chars(10)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %s", chars, err)
+				}
+				if len(b) != 10 || b[0] == '0' {
+					t.Fatalf("%s: got %q; want 10 characters without a leading 0", chars, b)
+				}
+				for _, v := range b {
+					if !g.Charset.Contains(v) {
+						t.Fatalf("%s: %q: %q is not in %q", chars, b, v, g.Charset)
+					}
+				}
+			}
+		}
+	}
+	g, _ := NewGenerator(6, false, "digits")
+	g.NoLeadingZero = true
+	if e, expected := g.Entropy(6), math.Log2(900000); math.Abs(e-expected) > 1e-9 {
+		t.Errorf("got %f; want %f", e, expected)
+	}
+	if b, _ := g.Chars(0); len(b) != 0 {
+		t.Errorf("got %q; want no characters", b)
+	}
+	g.Charset = randchars.MustCharset("0")
+	if _, err := g.Chars(6); err == nil {
+		t.Error("a charset of 0: got no error")
+	}
+}
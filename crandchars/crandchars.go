@@ -1,45 +1,55 @@
 // Package crandchars generates a chunk of random ASCII characters using a
 // CSPRNG. The supported ranges are: a-zA-Z0-9, a-z0-9, A-Z0-9, a-zA-Z, a-z,
-// A-Z, Base64, as defined in Table 1 of RFC 4648, and Base64URL, as defined in
-// Table 2 of RFC 4648.
+// A-Z, 0-9, hexadecimal, printable ASCII, Z85, Ascii85, Base64, as defined in
+// Table 1 of RFC 4648, Base64URL, as defined in Table 2 of RFC 4648, Base32
+// and Base32hex, as defined in Tables 3 and 4 of RFC 4648, Crockford's Base32,
+// and Base58. Any other set of ASCII characters can be used by passing a
+// randchars.Charset to Chars. Non-ASCII alphabets, including those with more
+// than 256 symbols, are supported by passing a randchars.RuneCharset to Runes.
 //
-// Calls to the package functions using the package global generator are
-// threadsafe.
+// Calls to the package functions are threadsafe. Each call uses one of a pool
+// of Generators, so concurrent calls don't contend with each other.
 package crandchars
 
 import (
 	"crypto/rand"
 	"fmt"
+	"io"
+	"math"
+	"slices"
 	"sync"
+
+	"github.com/mohae/randchars"
 )
 
 const (
-	alphaNum      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	alpha         = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	lowerAlphaNum = "abcdefghijklmnopqrstuvwxyz0123456789"
-	lowerAlpha    = "abcdefghijklmnopqrstuvwxyz"
-	upperAlphaNum = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	upperAlpha    = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	base64        = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789+/"
-	base64URL     = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"
 	// The default cache size for random bytes. This is the maximum number of bytes
 	// that linux's getrandom() guarantees to return without being interrupted by
 	// signals.
 	CacheSize = 256
 )
 
-var gen *Generator
-var genMu sync.Mutex
+// Errors returned by the error returning variants of the generation funcs.
+// They are the same values as the corresponding randchars errors.
+var (
+	ErrNegativeLength = randchars.ErrNegativeLength
+	ErrEmptyCharset   = randchars.ErrEmptyCharset
+	ErrEntropy        = randchars.ErrEntropy
+)
 
-func init() {
-	gen = New()
-}
+// pool holds the Generators used by the package funcs; each call gets its own,
+// so concurrent calls don't contend for a single Generator and its cache.
+var pool = sync.Pool{New: func() any { return New() }}
+
+// reader is the source of random bytes; it is only changed by tests.
+var reader = rand.Reader
 
 // Generator handles generation of random chars.
 type Generator struct {
 	cache     []byte
 	cacheSize int
 	current   int
+	err       error // the first CSPRNG error since it was last checked
 }
 
 // New returns a Generator that uses the default CacheSize.
@@ -47,202 +57,1008 @@ func New() *Generator {
 	return NewGenerator(CacheSize)
 }
 
-// NewGenerator returns a generator with a cache of n random bytes.
+// NewGenerator returns a generator with a cache of n random bytes. The cache
+// is filled on first use.
 func NewGenerator(n int) *Generator {
-	g := Generator{cache: make([]byte, n), cacheSize: n}
-	g.read()
-	return &g
+	return &Generator{cache: make([]byte, n), cacheSize: n, current: n}
 }
 
-// AlphaNum returns a randomly generated []byte of length n using a-zA-Z0-9.
-// This will panic if n < 0.
-func (g *Generator) AlphaNum(n int) []byte {
+// Chars returns a randomly generated []byte of length n using the characters
+// in cs. This will panic if n < 0 or cs is empty.
+func (g *Generator) Chars(cs randchars.Charset, n int) []byte {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	b := make([]byte, n)
-	for i := 0; i < n; i++ {
-		b[i] = alphaNum[g.intN(uint8(len(alphaNum)))]
+	if cs.Len() == 0 {
+		panic(randchars.ErrEmptyCharset)
 	}
+	b := make([]byte, n)
+	g.FillChars(b, cs)
 	return b
 }
 
-// Alpha returns a randomly generated []byte of length n using a-zA-Z. This
-// will panic if n < 0.
-func (g *Generator) Alpha(n int) []byte {
+// Generate returns a randomly generated []byte of length n using the
+// characters in cs. Unlike Chars, an error is returned instead of panicking:
+// ErrNegativeLength if n < 0, ErrEmptyCharset if cs is empty, and an error
+// wrapping ErrEntropy if random bytes could not be read from the CSPRNG.
+func (g *Generator) Generate(cs randchars.Charset, n int) ([]byte, error) {
 	if n < 0 {
-		panic(fmt.Sprintf("%d: value out of bounds", n))
+		return nil, ErrNegativeLength
+	}
+	if cs.Len() == 0 {
+		return nil, ErrEmptyCharset
 	}
 	b := make([]byte, n)
-	for i := 0; i < n; i++ {
-		b[i] = alpha[g.intN(uint8(len(alpha)))]
+	g.fillChars(b, cs)
+	if err := g.takeErr(); err != nil {
+		return nil, err
 	}
-	return b
+	return b, nil
 }
 
-// LowerAlphaNum returns a randomly generated []byte of length n using a-z0-9.
-// This will panic if n < 0.
-func (g *Generator) LowerAlphaNum(n int) []byte {
+// AppendChars appends n randomly generated characters using the characters in
+// cs to dst and returns the extended slice. If dst has sufficient capacity, no
+// allocation is done. This will panic if n < 0 or cs is empty.
+func (g *Generator) AppendChars(dst []byte, cs randchars.Charset, n int) []byte {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	b := make([]byte, n)
-	for i := 0; i < n; i++ {
-		b[i] = lowerAlphaNum[g.intN(uint8(len(lowerAlphaNum)))]
+	l := len(dst)
+	dst = slices.Grow(dst, n)[:l+n]
+	g.FillChars(dst[l:], cs)
+	return dst
+}
+
+// FillChars fills dst with randomly generated characters using the characters
+// in cs. This will panic if cs is empty.
+func (g *Generator) FillChars(dst []byte, cs randchars.Charset) {
+	if cs.Len() == 0 {
+		panic(randchars.ErrEmptyCharset)
+	}
+	g.fillChars(dst, cs)
+	if err := g.takeErr(); err != nil {
+		panic(err)
 	}
-	return b
 }
 
-// LowerAlpha returns a randomly generated []byte of length n using a-z. This
-// will panic if n < 0
-func (g *Generator) LowerAlpha(n int) []byte {
-	if n < 0 {
+// ReadChars fills dst with randomly generated characters using the
+// characters in cs; it implements randchars.CharsReader. Unlike FillChars, an
+// error is returned instead of panicking: ErrEmptyCharset if cs is empty, and
+// an error wrapping ErrEntropy if random bytes could not be read from the
+// CSPRNG, in which case dst must be discarded.
+func (g *Generator) ReadChars(dst []byte, cs randchars.Charset) error {
+	if cs.Len() == 0 {
+		return ErrEmptyCharset
+	}
+	g.fillChars(dst, cs)
+	return g.takeErr()
+}
+
+func (g *Generator) fillChars(dst []byte, cs randchars.Charset) {
+	chars := cs.String()
+	bound := uint8(len(chars))
+	threshold := cs.Threshold8()
+	for i := range dst {
+		dst[i] = chars[g.intN(bound, threshold)]
+	}
+}
+
+// IntN returns an unbiased random int in [0, n). This will panic if n <= 0.
+func (g *Generator) IntN(n int) int {
+	if n <= 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	b := make([]byte, n)
-	for i := 0; i < n; i++ {
-		b[i] = lowerAlpha[g.intN(uint8(len(lowerAlpha)))]
+	var v int
+	switch {
+	case n < 256:
+		bound := uint8(n)
+		v = g.intN(bound, -bound%bound)
+	case uint64(n) <= math.MaxUint32:
+		bound := uint32(n)
+		v = g.intN32(bound, -bound%bound)
+	default:
+		bound := uint64(n)
+		v = g.intN64(bound, -bound%bound)
 	}
-	return b
+	if err := g.takeErr(); err != nil {
+		panic(err)
+	}
+	return v
 }
 
-// UpperAlphaNum returns a randomly generated []byte of length n using A-Z0-9.
-// This will panic if n < 0.
-func (g *Generator) UpperAlphaNum(n int) []byte {
+// Runes returns n randomly generated runes using the runes in cs. This will
+// panic if n < 0 or cs is empty.
+func (g *Generator) Runes(cs randchars.RuneCharset, n int) []rune {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	b := make([]byte, n)
-	for i := 0; i < n; i++ {
-		b[i] = upperAlphaNum[g.intN(uint8(len(upperAlphaNum)))]
+	if cs.Len() == 0 {
+		panic(randchars.ErrEmptyCharset)
 	}
-	return b
+	r := g.runes(cs, n)
+	if err := g.takeErr(); err != nil {
+		panic(err)
+	}
+	return r
 }
 
-// UpperAlpha returns a randomly generated []byte of length n using A-Z. This
-// will panic if n < 0.
-func (g *Generator) UpperAlpha(n int) []byte {
+// GenerateRunes returns n randomly generated runes using the runes in cs.
+// Unlike Runes, an error is returned instead of panicking: ErrNegativeLength
+// if n < 0, ErrEmptyCharset if cs is empty, and an error wrapping ErrEntropy
+// if random bytes could not be read from the CSPRNG.
+func (g *Generator) GenerateRunes(cs randchars.RuneCharset, n int) ([]rune, error) {
 	if n < 0 {
-		panic(fmt.Sprintf("%d: value out of bounds", n))
+		return nil, ErrNegativeLength
 	}
-	b := make([]byte, n)
+	if cs.Len() == 0 {
+		return nil, ErrEmptyCharset
+	}
+	r := g.runes(cs, n)
+	if err := g.takeErr(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (g *Generator) runes(cs randchars.RuneCharset, n int) []rune {
+	r := make([]rune, n)
+	// use a single byte per rune when possible
+	if cs.Len() < 256 {
+		bound := uint8(cs.Len())
+		threshold := -bound % bound
+		for i := 0; i < n; i++ {
+			r[i] = cs.Rune(g.intN(bound, threshold))
+		}
+		return r
+	}
+	bound := uint32(cs.Len())
+	threshold := cs.Threshold32()
 	for i := 0; i < n; i++ {
-		b[i] = upperAlpha[g.intN(uint8(len(upperAlpha)))]
+		r[i] = cs.Rune(g.intN32(bound, threshold))
 	}
-	return b
+	return r
+}
+
+// RuneString returns a string of n randomly generated runes using the runes
+// in cs. The length of the string, in bytes, depends on the runes chosen. This
+// will panic if n < 0 or cs is empty.
+func (g *Generator) RuneString(cs randchars.RuneCharset, n int) string {
+	return string(g.Runes(cs, n))
+}
+
+// AlphaNum returns a randomly generated []byte of length n using a-zA-Z0-9.
+// This will panic if n < 0.
+func (g *Generator) AlphaNum(n int) []byte {
+	return g.Chars(randchars.AlphaNumCharset, n)
+}
+
+// Alpha returns a randomly generated []byte of length n using a-zA-Z. This
+// will panic if n < 0.
+func (g *Generator) Alpha(n int) []byte {
+	return g.Chars(randchars.AlphaCharset, n)
+}
+
+// LowerAlphaNum returns a randomly generated []byte of length n using a-z0-9.
+// This will panic if n < 0.
+func (g *Generator) LowerAlphaNum(n int) []byte {
+	return g.Chars(randchars.LowerAlphaNumCharset, n)
+}
+
+// LowerAlpha returns a randomly generated []byte of length n using a-z. This
+// will panic if n < 0
+func (g *Generator) LowerAlpha(n int) []byte {
+	return g.Chars(randchars.LowerAlphaCharset, n)
+}
+
+// UpperAlphaNum returns a randomly generated []byte of length n using A-Z0-9.
+// This will panic if n < 0.
+func (g *Generator) UpperAlphaNum(n int) []byte {
+	return g.Chars(randchars.UpperAlphaNumCharset, n)
+}
+
+// UpperAlpha returns a randomly generated []byte of length n using A-Z. This
+// will panic if n < 0.
+func (g *Generator) UpperAlpha(n int) []byte {
+	return g.Chars(randchars.UpperAlphaCharset, n)
 }
 
 // Base64 returns a randomly generated []byte of length n using Base64, as
 // defined in Table 2 of RFC 4648. This will panic if n < 0.
 func (g *Generator) Base64(n int) []byte {
+	return g.Chars(randchars.Base64Charset, n)
+}
+
+// Base64URL returns a randomly generated []byte of length n using Base64URL,
+// as defined in Table 2 of RFC 4648. This will panic if n < 0.
+func (g *Generator) Base64URL(n int) []byte {
+	return g.Chars(randchars.Base64URLCharset, n)
+}
+
+// Digits returns a randomly generated []byte of length n using 0-9. This will
+// panic if n < 0.
+func (g *Generator) Digits(n int) []byte {
+	return g.Chars(randchars.DigitsCharset, n)
+}
+
+// LowerHex returns a randomly generated []byte of length n using 0-9a-f. This
+// will panic if n < 0.
+func (g *Generator) LowerHex(n int) []byte {
+	return g.Chars(randchars.LowerHexCharset, n)
+}
+
+// UpperHex returns a randomly generated []byte of length n using 0-9A-F. This
+// will panic if n < 0.
+func (g *Generator) UpperHex(n int) []byte {
+	return g.Chars(randchars.UpperHexCharset, n)
+}
+
+// CharsNoLeadingZero returns a randomly generated []byte of length n using
+// the characters in cs whose first character isn't '0'; see
+// randchars.Generator.CharsNoLeadingZero. This will panic if n < 0 or cs has
+// no characters other than '0'.
+func (g *Generator) CharsNoLeadingZero(cs randchars.Charset, n int) []byte {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
 	b := make([]byte, n)
-	for i := 0; i < n; i++ {
-		b[i] = base64[g.intN(uint8(len(base64)))]
+	if n == 0 {
+		return b
 	}
+	b[0] = randchars.NonZero(g, cs)
+	g.FillChars(b[1:], cs)
 	return b
 }
 
-// Base64URL returns a randomly generated []byte of length n using Base64URL,
-// as defined in Table 2 of RFC 4648. This will panic if n < 0.
-func (g *Generator) Base64URL(n int) []byte {
+// Z85 returns a randomly generated []byte of length n using Z85, ZeroMQ's
+// Base85 alphabet. This will panic if n < 0.
+func (g *Generator) Z85(n int) []byte {
+	return g.Chars(randchars.Z85Charset, n)
+}
+
+// Ascii85 returns a randomly generated []byte of length n using the Ascii85
+// alphabet, ! to u. This will panic if n < 0.
+func (g *Generator) Ascii85(n int) []byte {
+	return g.Chars(randchars.Ascii85Charset, n)
+}
+
+// Printable returns a randomly generated []byte of length n using the
+// printable ASCII characters, ! to ~; space is excluded. This will panic if
+// n < 0.
+func (g *Generator) Printable(n int) []byte {
+	return g.Chars(randchars.PrintableCharset, n)
+}
+
+// ShellSafe returns a randomly generated []byte of length n using the
+// characters that are safe in an unquoted shell word; see
+// randchars.ShellSafeCharset. This will panic if n < 0.
+func (g *Generator) ShellSafe(n int) []byte {
+	return g.Chars(randchars.ShellSafeCharset, n)
+}
+
+// URLPath returns a randomly generated []byte of length n using the characters
+// that are safe in a URL path segment; see randchars.URLPathCharset. This will
+// panic if n < 0.
+func (g *Generator) URLPath(n int) []byte {
+	return g.Chars(randchars.URLPathCharset, n)
+}
+
+// JSONSafe returns a randomly generated []byte of length n using the characters
+// that are safe in a JSON string; see randchars.JSONSafeCharset. This will
+// panic if n < 0.
+func (g *Generator) JSONSafe(n int) []byte {
+	return g.Chars(randchars.JSONSafeCharset, n)
+}
+
+// XMLAttr returns a randomly generated []byte of length n using the characters
+// that are safe in an XML attribute value; see randchars.XMLAttrCharset. This
+// will panic if n < 0.
+func (g *Generator) XMLAttr(n int) []byte {
+	return g.Chars(randchars.XMLAttrCharset, n)
+}
+
+// DSNPassword returns a randomly generated []byte of length n using the
+// characters that are safe in a DSN password or a SQL string literal; see
+// randchars.DSNPasswordCharset. This will panic if n < 0.
+func (g *Generator) DSNPassword(n int) []byte {
+	return g.Chars(randchars.DSNPasswordCharset, n)
+}
+
+// Base32 returns a randomly generated []byte of length n using Base32, as
+// defined in Table 3 of RFC 4648. This will panic if n < 0.
+func (g *Generator) Base32(n int) []byte {
+	return g.Chars(randchars.Base32Charset, n)
+}
+
+// Base32Hex returns a randomly generated []byte of length n using Base32hex,
+// as defined in Table 4 of RFC 4648. This will panic if n < 0.
+func (g *Generator) Base32Hex(n int) []byte {
+	return g.Chars(randchars.Base32HexCharset, n)
+}
+
+// Crockford returns a randomly generated []byte of length n using Crockford's
+// Base32, https://www.crockford.com/base32.html. This will panic if n < 0.
+func (g *Generator) Crockford(n int) []byte {
+	return g.Chars(randchars.CrockfordCharset, n)
+}
+
+// Base58 returns a randomly generated []byte of length n using Bitcoin's
+// Base58 alphabet. This will panic if n < 0.
+func (g *Generator) Base58(n int) []byte {
+	return g.Chars(randchars.Base58Charset, n)
+}
+
+// Base58Flickr returns a randomly generated []byte of length n using
+// Flickr's Base58 alphabet. This will panic if n < 0.
+func (g *Generator) Base58Flickr(n int) []byte {
+	return g.Chars(randchars.Base58FlickrCharset, n)
+}
+
+// Base58Check returns the Base58Check encoding, see
+// randchars.EncodeBase58Check, of a payload of n random bytes. This will
+// panic if n < 0.
+func (g *Generator) Base58Check(n int) []byte {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	b := make([]byte, n)
-	for i := 0; i < n; i++ {
-		b[i] = base64URL[g.intN(uint8(len(base64)))]
+	payload := make([]byte, n)
+	for i := range payload {
+		payload[i] = g.next()
 	}
-	return b
+	if err := g.takeErr(); err != nil {
+		panic(err)
+	}
+	return randchars.EncodeBase58Check(payload)
+}
+
+// CrockfordWithCheck returns n randomly generated characters using
+// Crockford's Base32 followed by their check symbol; see
+// randchars.ValidateCrockford. This will panic if n < 1.
+func (g *Generator) CrockfordWithCheck(n int) []byte {
+	if n < 1 {
+		panic(fmt.Sprintf("%d: value out of bounds", n))
+	}
+	return randchars.AppendCrockfordCheck(g.AppendCrockford(make([]byte, 0, n+1), n))
+}
+
+// AppendAlphaNum appends n randomly generated characters using a-zA-Z0-9 to
+// dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendAlphaNum(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.AlphaNumCharset, n)
+}
+
+// FillAlphaNum fills dst with randomly generated characters using a-zA-Z0-9.
+func (g *Generator) FillAlphaNum(dst []byte) {
+	g.FillChars(dst, randchars.AlphaNumCharset)
+}
+
+// AppendAlpha appends n randomly generated characters using a-zA-Z to dst and
+// returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendAlpha(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.AlphaCharset, n)
+}
+
+// FillAlpha fills dst with randomly generated characters using a-zA-Z.
+func (g *Generator) FillAlpha(dst []byte) {
+	g.FillChars(dst, randchars.AlphaCharset)
+}
+
+// AppendLowerAlphaNum appends n randomly generated characters using a-z0-9 to
+// dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendLowerAlphaNum(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.LowerAlphaNumCharset, n)
+}
+
+// FillLowerAlphaNum fills dst with randomly generated characters using a-z0-9.
+func (g *Generator) FillLowerAlphaNum(dst []byte) {
+	g.FillChars(dst, randchars.LowerAlphaNumCharset)
+}
+
+// AppendLowerAlpha appends n randomly generated characters using a-z to dst
+// and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendLowerAlpha(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.LowerAlphaCharset, n)
+}
+
+// FillLowerAlpha fills dst with randomly generated characters using a-z.
+func (g *Generator) FillLowerAlpha(dst []byte) {
+	g.FillChars(dst, randchars.LowerAlphaCharset)
+}
+
+// AppendUpperAlphaNum appends n randomly generated characters using A-Z0-9 to
+// dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendUpperAlphaNum(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.UpperAlphaNumCharset, n)
+}
+
+// FillUpperAlphaNum fills dst with randomly generated characters using A-Z0-9.
+func (g *Generator) FillUpperAlphaNum(dst []byte) {
+	g.FillChars(dst, randchars.UpperAlphaNumCharset)
+}
+
+// AppendUpperAlpha appends n randomly generated characters using A-Z to dst
+// and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendUpperAlpha(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.UpperAlphaCharset, n)
+}
+
+// FillUpperAlpha fills dst with randomly generated characters using A-Z.
+func (g *Generator) FillUpperAlpha(dst []byte) {
+	g.FillChars(dst, randchars.UpperAlphaCharset)
+}
+
+// AppendBase64 appends n randomly generated characters using Base64, as
+// defined in Table 1 of RFC 4648, to dst and returns the extended slice. This
+// will panic if n < 0.
+func (g *Generator) AppendBase64(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.Base64Charset, n)
+}
+
+// FillBase64 fills dst with randomly generated characters using Base64, as
+// defined in Table 1 of RFC 4648.
+func (g *Generator) FillBase64(dst []byte) {
+	g.FillChars(dst, randchars.Base64Charset)
+}
+
+// AppendBase64URL appends n randomly generated characters using Base64URL, as
+// defined in Table 2 of RFC 4648, to dst and returns the extended slice. This
+// will panic if n < 0.
+func (g *Generator) AppendBase64URL(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.Base64URLCharset, n)
+}
+
+// FillBase64URL fills dst with randomly generated characters using Base64URL,
+// as defined in Table 2 of RFC 4648.
+func (g *Generator) FillBase64URL(dst []byte) {
+	g.FillChars(dst, randchars.Base64URLCharset)
+}
+
+// AppendDigits appends n randomly generated characters using 0-9 to dst and
+// returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendDigits(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.DigitsCharset, n)
+}
+
+// FillDigits fills dst with randomly generated characters using 0-9.
+func (g *Generator) FillDigits(dst []byte) {
+	g.FillChars(dst, randchars.DigitsCharset)
+}
+
+// AppendLowerHex appends n randomly generated characters using 0-9a-f to dst
+// and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendLowerHex(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.LowerHexCharset, n)
+}
+
+// FillLowerHex fills dst with randomly generated characters using 0-9a-f.
+func (g *Generator) FillLowerHex(dst []byte) {
+	g.FillChars(dst, randchars.LowerHexCharset)
+}
+
+// AppendUpperHex appends n randomly generated characters using 0-9A-F to dst
+// and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendUpperHex(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.UpperHexCharset, n)
+}
+
+// FillUpperHex fills dst with randomly generated characters using 0-9A-F.
+func (g *Generator) FillUpperHex(dst []byte) {
+	g.FillChars(dst, randchars.UpperHexCharset)
+}
+
+// AppendZ85 appends n randomly generated characters using Z85 to dst and
+// returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendZ85(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.Z85Charset, n)
+}
+
+// FillZ85 fills dst with randomly generated characters using Z85.
+func (g *Generator) FillZ85(dst []byte) {
+	g.FillChars(dst, randchars.Z85Charset)
+}
+
+// AppendAscii85 appends n randomly generated characters using Ascii85 to dst
+// and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendAscii85(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.Ascii85Charset, n)
+}
+
+// FillAscii85 fills dst with randomly generated characters using Ascii85.
+func (g *Generator) FillAscii85(dst []byte) {
+	g.FillChars(dst, randchars.Ascii85Charset)
+}
+
+// AppendPrintable appends n randomly generated printable ASCII characters to
+// dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendPrintable(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.PrintableCharset, n)
+}
+
+// FillPrintable fills dst with randomly generated printable ASCII characters.
+func (g *Generator) FillPrintable(dst []byte) {
+	g.FillChars(dst, randchars.PrintableCharset)
+}
+
+// AppendShellSafe appends n randomly generated characters using
+// randchars.ShellSafeCharset to dst and returns the extended slice. This will
+// panic if n < 0.
+func (g *Generator) AppendShellSafe(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.ShellSafeCharset, n)
+}
+
+// FillShellSafe fills dst with randomly generated characters using
+// randchars.ShellSafeCharset.
+func (g *Generator) FillShellSafe(dst []byte) {
+	g.FillChars(dst, randchars.ShellSafeCharset)
+}
+
+// AppendURLPath appends n randomly generated characters using
+// randchars.URLPathCharset to dst and returns the extended slice. This will
+// panic if n < 0.
+func (g *Generator) AppendURLPath(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.URLPathCharset, n)
+}
+
+// FillURLPath fills dst with randomly generated characters using
+// randchars.URLPathCharset.
+func (g *Generator) FillURLPath(dst []byte) {
+	g.FillChars(dst, randchars.URLPathCharset)
+}
+
+// AppendJSONSafe appends n randomly generated characters using
+// randchars.JSONSafeCharset to dst and returns the extended slice. This will
+// panic if n < 0.
+func (g *Generator) AppendJSONSafe(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.JSONSafeCharset, n)
+}
+
+// FillJSONSafe fills dst with randomly generated characters using
+// randchars.JSONSafeCharset.
+func (g *Generator) FillJSONSafe(dst []byte) {
+	g.FillChars(dst, randchars.JSONSafeCharset)
+}
+
+// AppendXMLAttr appends n randomly generated characters using
+// randchars.XMLAttrCharset to dst and returns the extended slice. This will
+// panic if n < 0.
+func (g *Generator) AppendXMLAttr(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.XMLAttrCharset, n)
+}
+
+// FillXMLAttr fills dst with randomly generated characters using
+// randchars.XMLAttrCharset.
+func (g *Generator) FillXMLAttr(dst []byte) {
+	g.FillChars(dst, randchars.XMLAttrCharset)
+}
+
+// AppendDSNPassword appends n randomly generated characters using
+// randchars.DSNPasswordCharset to dst and returns the extended slice. This
+// will panic if n < 0.
+func (g *Generator) AppendDSNPassword(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.DSNPasswordCharset, n)
+}
+
+// FillDSNPassword fills dst with randomly generated characters using
+// randchars.DSNPasswordCharset.
+func (g *Generator) FillDSNPassword(dst []byte) {
+	g.FillChars(dst, randchars.DSNPasswordCharset)
+}
+
+// AppendBase32 appends n randomly generated characters using Base32, as
+// defined in Table 3 of RFC 4648, to dst and returns the extended slice. This
+// will panic if n < 0.
+func (g *Generator) AppendBase32(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.Base32Charset, n)
+}
+
+// FillBase32 fills dst with randomly generated characters using Base32, as
+// defined in Table 3 of RFC 4648.
+func (g *Generator) FillBase32(dst []byte) {
+	g.FillChars(dst, randchars.Base32Charset)
+}
+
+// AppendBase32Hex appends n randomly generated characters using Base32hex, as
+// defined in Table 4 of RFC 4648, to dst and returns the extended slice. This
+// will panic if n < 0.
+func (g *Generator) AppendBase32Hex(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.Base32HexCharset, n)
+}
+
+// FillBase32Hex fills dst with randomly generated characters using Base32hex,
+// as defined in Table 4 of RFC 4648.
+func (g *Generator) FillBase32Hex(dst []byte) {
+	g.FillChars(dst, randchars.Base32HexCharset)
+}
+
+// AppendCrockford appends n randomly generated characters using Crockford's
+// Base32 to dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendCrockford(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.CrockfordCharset, n)
+}
+
+// FillCrockford fills dst with randomly generated characters using
+// Crockford's Base32.
+func (g *Generator) FillCrockford(dst []byte) {
+	g.FillChars(dst, randchars.CrockfordCharset)
+}
+
+// AppendBase58 appends n randomly generated characters using Bitcoin's Base58
+// alphabet to dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendBase58(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.Base58Charset, n)
+}
+
+// FillBase58 fills dst with randomly generated characters using Bitcoin's
+// Base58 alphabet.
+func (g *Generator) FillBase58(dst []byte) {
+	g.FillChars(dst, randchars.Base58Charset)
+}
+
+// AppendBase58Flickr appends n randomly generated characters using Flickr's
+// Base58 alphabet to dst and returns the extended slice. This will panic if
+// n < 0.
+func (g *Generator) AppendBase58Flickr(dst []byte, n int) []byte {
+	return g.AppendChars(dst, randchars.Base58FlickrCharset, n)
+}
+
+// FillBase58Flickr fills dst with randomly generated characters using
+// Flickr's Base58 alphabet.
+func (g *Generator) FillBase58Flickr(dst []byte) {
+	g.FillChars(dst, randchars.Base58FlickrCharset)
+}
+
+// Chars returns a randomly generated []byte of length n using the characters
+// in cs. This will panic if n < 0 or cs is empty.
+func Chars(cs randchars.Charset, n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Chars(cs, n)
+}
+
+// IntN returns an unbiased random int in [0, n). This will panic if n <= 0.
+func IntN(n int) int {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.IntN(n)
+}
+
+// Generate returns a randomly generated []byte of length n using the
+// characters in cs. Unlike Chars, an error is returned instead of panicking:
+// ErrNegativeLength if n < 0, ErrEmptyCharset if cs is empty, and an error
+// wrapping ErrEntropy if random bytes could not be read from the CSPRNG.
+func Generate(cs randchars.Charset, n int) ([]byte, error) {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Generate(cs, n)
+}
+
+// GenerateRunes returns n randomly generated runes using the runes in cs.
+// Unlike Runes, an error is returned instead of panicking: ErrNegativeLength
+// if n < 0, ErrEmptyCharset if cs is empty, and an error wrapping ErrEntropy
+// if random bytes could not be read from the CSPRNG.
+func GenerateRunes(cs randchars.RuneCharset, n int) ([]rune, error) {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.GenerateRunes(cs, n)
+}
+
+// Runes returns n randomly generated runes using the runes in cs. This will
+// panic if n < 0 or cs is empty.
+func Runes(cs randchars.RuneCharset, n int) []rune {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Runes(cs, n)
+}
+
+// RuneString returns a string of n randomly generated runes using the runes
+// in cs. This will panic if n < 0 or cs is empty.
+func RuneString(cs randchars.RuneCharset, n int) string {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.RuneString(cs, n)
 }
 
 // AlphaNum returns a randomly generated []byte of length n using a-zA-Z0-9.
 // This will panic if n < 0.
 func AlphaNum(n int) []byte {
-	genMu.Lock()
-	defer genMu.Unlock()
-	return gen.AlphaNum(n)
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.AlphaNum(n)
 }
 
 // Alpha returns a randomly generated []byte of length n using a-zA-Z. This
 // will panic if n < 0.
 func Alpha(n int) []byte {
-	genMu.Lock()
-	defer genMu.Unlock()
-	return gen.Alpha(n)
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Alpha(n)
 }
 
 // LowerAlphaNum returns a randomly generated []byte of length n using a-z0-9.
 // This will panic if n < 0.
 func LowerAlphaNum(n int) []byte {
-	genMu.Lock()
-	defer genMu.Unlock()
-	return gen.LowerAlphaNum(n)
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.LowerAlphaNum(n)
 }
 
 // LowerAlpha returns a randomly generated []byte of length n using a-z. This
 // will panic if n < 0.
 func LowerAlpha(n int) []byte {
-	genMu.Lock()
-	defer genMu.Unlock()
-	return gen.LowerAlpha(n)
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.LowerAlpha(n)
 }
 
 // UpperAlphaNum returns a randomly generated []byte of length n using A-Z0-9.
 // This will panic if n < 0.
 func UpperAlphaNum(n int) []byte {
-	genMu.Lock()
-	defer genMu.Unlock()
-	return gen.UpperAlphaNum(n)
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.UpperAlphaNum(n)
 }
 
 // UpperAlpha returns a randomly generated []byte of length n using A-Z. This
 // will panic if n < 0.
 func UpperAlpha(n int) []byte {
-	genMu.Lock()
-	defer genMu.Unlock()
-	return gen.UpperAlpha(n)
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.UpperAlpha(n)
 }
 
 // Base64 returns a randomly generated []byte of length n using Base64, as
 // defined in Table 2 of RFC 4648. This will panic if n < 0.
 func Base64(n int) []byte {
-	genMu.Lock()
-	defer genMu.Unlock()
-	return gen.Base64(n)
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Base64(n)
 }
 
 // Base64URL returns a randomly generated []byte of length n using Base64URL,
 // as defined in Table 2 of RFC 4648. This will panic if n < 0.
 func Base64URL(n int) []byte {
-	genMu.Lock()
-	defer genMu.Unlock()
-	return gen.Base64URL(n)
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Base64URL(n)
+}
+
+// Digits returns a randomly generated []byte of length n using 0-9. This will
+// panic if n < 0.
+func Digits(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Digits(n)
+}
+
+// LowerHex returns a randomly generated []byte of length n using 0-9a-f. This
+// will panic if n < 0.
+func LowerHex(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.LowerHex(n)
+}
+
+// UpperHex returns a randomly generated []byte of length n using 0-9A-F. This
+// will panic if n < 0.
+func UpperHex(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.UpperHex(n)
+}
+
+// CharsNoLeadingZero returns a randomly generated []byte of length n using
+// the characters in cs whose first character isn't '0'. This will panic if
+// n < 0 or cs has no characters other than '0'.
+func CharsNoLeadingZero(cs randchars.Charset, n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.CharsNoLeadingZero(cs, n)
+}
+
+// Z85 returns a randomly generated []byte of length n using Z85. This will
+// panic if n < 0.
+func Z85(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Z85(n)
+}
+
+// Ascii85 returns a randomly generated []byte of length n using Ascii85. This
+// will panic if n < 0.
+func Ascii85(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Ascii85(n)
+}
+
+// Printable returns a randomly generated []byte of length n using the
+// printable ASCII characters. This will panic if n < 0.
+func Printable(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Printable(n)
+}
+
+// ShellSafe returns a randomly generated []byte of length n using
+// randchars.ShellSafeCharset. This will panic if n < 0.
+func ShellSafe(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.ShellSafe(n)
+}
+
+// URLPath returns a randomly generated []byte of length n using
+// randchars.URLPathCharset. This will panic if n < 0.
+func URLPath(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.URLPath(n)
+}
+
+// JSONSafe returns a randomly generated []byte of length n using
+// randchars.JSONSafeCharset. This will panic if n < 0.
+func JSONSafe(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.JSONSafe(n)
+}
+
+// XMLAttr returns a randomly generated []byte of length n using
+// randchars.XMLAttrCharset. This will panic if n < 0.
+func XMLAttr(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.XMLAttr(n)
 }
 
-// read fills the cache.
-func (g *Generator) read() {
-	_, err := rand.Read(g.cache)
+// DSNPassword returns a randomly generated []byte of length n using
+// randchars.DSNPasswordCharset. This will panic if n < 0.
+func DSNPassword(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.DSNPassword(n)
+}
+
+// Base32 returns a randomly generated []byte of length n using Base32, as
+// defined in Table 3 of RFC 4648. This will panic if n < 0.
+func Base32(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Base32(n)
+}
+
+// Base32Hex returns a randomly generated []byte of length n using Base32hex,
+// as defined in Table 4 of RFC 4648. This will panic if n < 0.
+func Base32Hex(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Base32Hex(n)
+}
+
+// Crockford returns a randomly generated []byte of length n using Crockford's
+// Base32. This will panic if n < 0.
+func Crockford(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Crockford(n)
+}
+
+// CrockfordWithCheck returns n randomly generated characters using
+// Crockford's Base32 followed by their check symbol. This will panic if
+// n < 1.
+func CrockfordWithCheck(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.CrockfordWithCheck(n)
+}
+
+// Base58 returns a randomly generated []byte of length n using Bitcoin's
+// Base58 alphabet. This will panic if n < 0.
+func Base58(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Base58(n)
+}
+
+// Base58Flickr returns a randomly generated []byte of length n using
+// Flickr's Base58 alphabet. This will panic if n < 0.
+func Base58Flickr(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Base58Flickr(n)
+}
+
+// Base58Check returns the Base58Check encoding of a payload of n random
+// bytes; see randchars.EncodeBase58Check. This will panic if n < 0.
+func Base58Check(n int) []byte {
+	g := pool.Get().(*Generator)
+	defer pool.Put(g)
+	return g.Base58Check(n)
+}
+
+// read fills the cache and reports whether it succeeded. If the CSPRNG fails,
+// the error is saved for takeErr; the output generated since then must be
+// discarded.
+func (g *Generator) read() bool {
+	_, err := io.ReadFull(reader, g.cache)
 	if err != nil {
-		panic(err)
+		if g.err == nil {
+			g.err = fmt.Errorf("%w: %s", ErrEntropy, err)
+		}
+		return false
 	}
+	return true
 }
 
-// intN gets an unbiased value from the cache of random byte values.
-func (g *Generator) intN(bound uint8) int {
-	threshold := -bound % bound
+// takeErr returns, and clears, the saved CSPRNG error, if any.
+func (g *Generator) takeErr() error {
+	err := g.err
+	g.err = nil
+	return err
+}
+
+// next returns the next random byte from the cache, replenishing the cache
+// when it is exhausted. If the cache can't be replenished, 0 is returned and
+// the cache stays exhausted, so no byte is used twice and the next call
+// retries the read.
+func (g *Generator) next() byte {
+	// if we're at the end; replenish the cache
+	if g.current >= g.cacheSize {
+		if !g.read() {
+			return 0
+		}
+		g.current = 0
+	}
+	n := g.cache[g.current]
+	g.current++
+	return n
+}
+
+// intN gets an unbiased value from the cache of random byte values. Values
+// less than threshold are rejected.
+func (g *Generator) intN(bound, threshold uint8) int {
+	for {
+		n := g.next()
+		if n >= threshold {
+			return int(n % bound)
+		}
+		// the cache can't be replenished; the output will be discarded
+		if g.err != nil {
+			return 0
+		}
+	}
+}
+
+// intN32 gets an unbiased value from the cache of random byte values using 4
+// bytes per draw; this supports bounds > 256. Values less than threshold are
+// rejected.
+func (g *Generator) intN32(bound, threshold uint32) int {
+	for {
+		n := uint32(g.next()) | uint32(g.next())<<8 | uint32(g.next())<<16 | uint32(g.next())<<24
+		if n >= threshold {
+			return int(n % bound)
+		}
+		// the cache can't be replenished; the output will be discarded
+		if g.err != nil {
+			return 0
+		}
+	}
+}
+
+// intN64 gets an unbiased value from the cache of random byte values using 8
+// bytes per draw; this supports bounds > 2^32. Values less than threshold are
+// rejected.
+func (g *Generator) intN64(bound, threshold uint64) int {
 	for {
-		n := g.cache[g.current]
-		g.current++
-		// if we're at the end; replenish the cache
-		if g.current >= g.cacheSize {
-			g.read()
-			g.current = 0
+		var n uint64
+		for i := 0; i < 8; i++ {
+			n |= uint64(g.next()) << (8 * i)
 		}
 		if n >= threshold {
 			return int(n % bound)
 		}
+		// the cache can't be replenished; the output will be discarded
+		if g.err != nil {
+			return 0
+		}
 	}
 }
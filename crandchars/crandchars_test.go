@@ -1,9 +1,214 @@
 package crandchars
 
 import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mohae/randchars"
 )
 
+func TestChars(t *testing.T) {
+	cs := randchars.MustCharset("abcdefghijklmnopqrstuvwxyz0123456789_")
+	g := New()
+	for _, n := range []int{0, 1, 2, 10, 1000} {
+		b := g.Chars(cs, n)
+		if len(b) != n {
+			t.Errorf("got len %d; want %d", len(b), n)
+		}
+		for _, c := range b {
+			if !cs.Contains(c) {
+				t.Errorf("%q: not in %q", c, cs)
+			}
+		}
+	}
+}
+
+func TestRunes(t *testing.T) {
+	g := New()
+	for _, table := range []*unicode.RangeTable{unicode.Greek, unicode.Han} {
+		cs, err := randchars.NewRuneCharsetFromTables(table)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		for _, n := range []int{0, 1, 2, 10, 1000} {
+			s := g.RuneString(cs, n)
+			if utf8.RuneCountInString(s) != n {
+				t.Errorf("got %d runes; want %d", utf8.RuneCountInString(s), n)
+			}
+			for _, r := range s {
+				if !unicode.Is(table, r) {
+					t.Errorf("%U: not in table", r)
+				}
+			}
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(randchars.NewReader(New(), randchars.Base64URLCharset), 1000))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 1000 {
+		t.Errorf("got %d bytes; want 1000", n)
+	}
+	for _, c := range buf.Bytes() {
+		if !randchars.Base64URLCharset.Contains(c) {
+			t.Errorf("%q: not in %q", c, randchars.Base64URLCharset)
+		}
+	}
+
+	// a CSPRNG failure is returned, not a panic
+	reader = errReader{}
+	defer func() { reader = rand.Reader }()
+	n, err = io.Copy(&buf, randchars.NewReader(New(), randchars.Base64URLCharset))
+	if !errors.Is(err, ErrEntropy) {
+		t.Errorf("got %v; want %v", err, ErrEntropy)
+	}
+	if n != 0 {
+		t.Errorf("got %d bytes; want 0", n)
+	}
+}
+
+func TestAppendFill(t *testing.T) {
+	g := New()
+	b := g.AppendAlphaNum([]byte("id-"), 10)
+	if len(b) != 13 || string(b[:3]) != "id-" {
+		t.Errorf("got %q; want id- followed by 10 chars", string(b))
+	}
+	for _, c := range b[3:] {
+		if !randchars.AlphaNumCharset.Contains(c) {
+			t.Errorf("%q: not in %q", c, randchars.AlphaNumCharset)
+		}
+	}
+	dst := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = g.AppendUpperAlphaNum(dst[:0], 64)
+		g.FillBase64URL(dst)
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocs; want 0", allocs)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("getrandom failed")
+}
+
+func TestGenerate(t *testing.T) {
+	g := New()
+	_, err := g.Generate(randchars.AlphaNumCharset, -1)
+	if err != ErrNegativeLength {
+		t.Errorf("got %v; want %v", err, ErrNegativeLength)
+	}
+	_, err = g.Generate(randchars.Charset{}, 1)
+	if err != ErrEmptyCharset {
+		t.Errorf("got %v; want %v", err, ErrEmptyCharset)
+	}
+	b, err := g.Generate(randchars.AlphaNumCharset, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(b) != 1000 {
+		t.Errorf("got len %d; want 1000", len(b))
+	}
+
+	reader = errReader{}
+	defer func() { reader = rand.Reader }()
+	g = New()
+	_, err = g.Generate(randchars.AlphaNumCharset, 10)
+	if !errors.Is(err, ErrEntropy) {
+		t.Errorf("got %v; want %v", err, ErrEntropy)
+	}
+	_, err = g.GenerateRunes(randchars.MustRuneCharset("αβγ"), 10)
+	if !errors.Is(err, ErrEntropy) {
+		t.Errorf("got %v; want %v", err, ErrEntropy)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic; got none")
+		}
+	}()
+	g.AlphaNum(10)
+}
+
+// byteReader reads an endless stream of one byte.
+type byteReader byte
+
+func (r byteReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+	return len(p), nil
+}
+
+func TestEntropyRetry(t *testing.T) {
+	defer func() { reader = rand.Reader }()
+	reader = byteReader(11)
+	g := NewGenerator(8)
+	for i := 0; i < 8; i++ {
+		g.next()
+	}
+	// a failed read doesn't reuse the cache
+	reader = errReader{}
+	if _, err := g.Generate(randchars.AlphaNumCharset, 4); !errors.Is(err, ErrEntropy) {
+		t.Errorf("got %v; want %v", err, ErrEntropy)
+	}
+	if g.current != g.cacheSize {
+		t.Errorf("got current %d; want %d", g.current, g.cacheSize)
+	}
+	// and the next call retries it
+	reader = byteReader(12)
+	b, err := g.Generate(randchars.DigitsCharset, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(b) != "2222" {
+		t.Errorf("got %q; want %q", b, "2222")
+	}
+}
+
+func TestIntN(t *testing.T) {
+	g := New()
+	for _, n := range []int{1, 2, 10, 255, 256, 1 << 20, 1<<32 + 1, 1 << 62} {
+		for i := 0; i < 100; i++ {
+			v := g.IntN(n)
+			if v < 0 || v >= n {
+				t.Errorf("%d: got %d; want a value in [0, %d)", n, v, n)
+			}
+		}
+	}
+}
+
+func TestUnambiguous(t *testing.T) {
+	g := New()
+	cs, err := randchars.UpperAlphaNumCharset.Exclude(randchars.Ambiguous + "AEIU")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, c := range g.Chars(cs, 1000) {
+		if strings.IndexByte(randchars.Ambiguous+"AEIU", c) >= 0 {
+			t.Errorf("%q: got excluded character %q", cs, c)
+		}
+	}
+	for _, c := range g.Chars(randchars.UnambiguousAlphaNumCharset, 1000) {
+		if strings.IndexByte(randchars.Ambiguous, c) >= 0 {
+			t.Errorf("got ambiguous character %q", c)
+		}
+	}
+}
+
 func BenchmarkAlphaNum_8(b *testing.B) {
 	g := New()
 	b.ResetTimer()
@@ -251,3 +456,340 @@ func BenchmarkBase64URL_64(b *testing.B) {
 		g.Base64URL(64)
 	}
 }
+
+func BenchmarkAppendAlphaNum_8(b *testing.B) {
+	g := New()
+	dst := make([]byte, 0, 8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = g.AppendAlphaNum(dst[:0], 8)
+	}
+}
+
+func BenchmarkFillAlphaNum_8(b *testing.B) {
+	g := New()
+	dst := make([]byte, 8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.FillAlphaNum(dst)
+	}
+}
+
+func BenchmarkFillBase64_8(b *testing.B) {
+	g := New()
+	dst := make([]byte, 8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.FillBase64(dst)
+	}
+}
+
+func BenchmarkAppendAlphaNum_16(b *testing.B) {
+	g := New()
+	dst := make([]byte, 0, 16)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = g.AppendAlphaNum(dst[:0], 16)
+	}
+}
+
+func BenchmarkFillAlphaNum_16(b *testing.B) {
+	g := New()
+	dst := make([]byte, 16)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.FillAlphaNum(dst)
+	}
+}
+
+func BenchmarkFillBase64_16(b *testing.B) {
+	g := New()
+	dst := make([]byte, 16)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.FillBase64(dst)
+	}
+}
+
+func BenchmarkAppendAlphaNum_32(b *testing.B) {
+	g := New()
+	dst := make([]byte, 0, 32)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = g.AppendAlphaNum(dst[:0], 32)
+	}
+}
+
+func BenchmarkFillAlphaNum_32(b *testing.B) {
+	g := New()
+	dst := make([]byte, 32)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.FillAlphaNum(dst)
+	}
+}
+
+func BenchmarkFillBase64_32(b *testing.B) {
+	g := New()
+	dst := make([]byte, 32)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.FillBase64(dst)
+	}
+}
+
+func BenchmarkAppendAlphaNum_64(b *testing.B) {
+	g := New()
+	dst := make([]byte, 0, 64)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = g.AppendAlphaNum(dst[:0], 64)
+	}
+}
+
+func BenchmarkFillAlphaNum_64(b *testing.B) {
+	g := New()
+	dst := make([]byte, 64)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.FillAlphaNum(dst)
+	}
+}
+
+func BenchmarkFillBase64_64(b *testing.B) {
+	g := New()
+	dst := make([]byte, 64)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.FillBase64(dst)
+	}
+}
+
+func TestPackageConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				b := AlphaNum(16)
+				if len(b) != 16 {
+					t.Errorf("got %d chars; want 16", len(b))
+					return
+				}
+				for _, c := range b {
+					if !unicode.IsLetter(rune(c)) && !unicode.IsDigit(rune(c)) {
+						t.Errorf("%q: not an alphanumeric character", c)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkPackageAlphaNumParallel_32(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			AlphaNum(32)
+		}
+	})
+}
+
+func TestAlphabets(t *testing.T) {
+	g := New()
+	fill := func(f func([]byte)) func(int) []byte {
+		return func(n int) []byte {
+			b := make([]byte, n)
+			f(b)
+			return b
+		}
+	}
+	tests := []struct {
+		name string
+		cs   randchars.Charset
+		gen  func(n int) []byte
+	}{
+		{"AlphaNum", randchars.AlphaNumCharset, g.AlphaNum},
+		{"AppendAlphaNum", randchars.AlphaNumCharset, func(n int) []byte { return g.AppendAlphaNum(nil, n) }},
+		{"FillAlphaNum", randchars.AlphaNumCharset, fill(g.FillAlphaNum)},
+		{"Alpha", randchars.AlphaCharset, g.Alpha},
+		{"AppendAlpha", randchars.AlphaCharset, func(n int) []byte { return g.AppendAlpha(nil, n) }},
+		{"FillAlpha", randchars.AlphaCharset, fill(g.FillAlpha)},
+		{"LowerAlphaNum", randchars.LowerAlphaNumCharset, g.LowerAlphaNum},
+		{"AppendLowerAlphaNum", randchars.LowerAlphaNumCharset, func(n int) []byte { return g.AppendLowerAlphaNum(nil, n) }},
+		{"FillLowerAlphaNum", randchars.LowerAlphaNumCharset, fill(g.FillLowerAlphaNum)},
+		{"LowerAlpha", randchars.LowerAlphaCharset, g.LowerAlpha},
+		{"AppendLowerAlpha", randchars.LowerAlphaCharset, func(n int) []byte { return g.AppendLowerAlpha(nil, n) }},
+		{"FillLowerAlpha", randchars.LowerAlphaCharset, fill(g.FillLowerAlpha)},
+		{"UpperAlphaNum", randchars.UpperAlphaNumCharset, g.UpperAlphaNum},
+		{"AppendUpperAlphaNum", randchars.UpperAlphaNumCharset, func(n int) []byte { return g.AppendUpperAlphaNum(nil, n) }},
+		{"FillUpperAlphaNum", randchars.UpperAlphaNumCharset, fill(g.FillUpperAlphaNum)},
+		{"UpperAlpha", randchars.UpperAlphaCharset, g.UpperAlpha},
+		{"AppendUpperAlpha", randchars.UpperAlphaCharset, func(n int) []byte { return g.AppendUpperAlpha(nil, n) }},
+		{"FillUpperAlpha", randchars.UpperAlphaCharset, fill(g.FillUpperAlpha)},
+		{"Base64", randchars.Base64Charset, g.Base64},
+		{"AppendBase64", randchars.Base64Charset, func(n int) []byte { return g.AppendBase64(nil, n) }},
+		{"FillBase64", randchars.Base64Charset, fill(g.FillBase64)},
+		{"Base64URL", randchars.Base64URLCharset, g.Base64URL},
+		{"AppendBase64URL", randchars.Base64URLCharset, func(n int) []byte { return g.AppendBase64URL(nil, n) }},
+		{"FillBase64URL", randchars.Base64URLCharset, fill(g.FillBase64URL)},
+		{"Digits", randchars.DigitsCharset, g.Digits},
+		{"AppendDigits", randchars.DigitsCharset, func(n int) []byte { return g.AppendDigits(nil, n) }},
+		{"FillDigits", randchars.DigitsCharset, fill(g.FillDigits)},
+		{"LowerHex", randchars.LowerHexCharset, g.LowerHex},
+		{"AppendLowerHex", randchars.LowerHexCharset, func(n int) []byte { return g.AppendLowerHex(nil, n) }},
+		{"FillLowerHex", randchars.LowerHexCharset, fill(g.FillLowerHex)},
+		{"UpperHex", randchars.UpperHexCharset, g.UpperHex},
+		{"AppendUpperHex", randchars.UpperHexCharset, func(n int) []byte { return g.AppendUpperHex(nil, n) }},
+		{"FillUpperHex", randchars.UpperHexCharset, fill(g.FillUpperHex)},
+		{"CharsNoLeadingZero", randchars.DigitsCharset, func(n int) []byte { return g.CharsNoLeadingZero(randchars.DigitsCharset, n) }},
+		{"Z85", randchars.Z85Charset, g.Z85},
+		{"AppendZ85", randchars.Z85Charset, func(n int) []byte { return g.AppendZ85(nil, n) }},
+		{"FillZ85", randchars.Z85Charset, fill(g.FillZ85)},
+		{"Ascii85", randchars.Ascii85Charset, g.Ascii85},
+		{"AppendAscii85", randchars.Ascii85Charset, func(n int) []byte { return g.AppendAscii85(nil, n) }},
+		{"FillAscii85", randchars.Ascii85Charset, fill(g.FillAscii85)},
+		{"Printable", randchars.PrintableCharset, g.Printable},
+		{"AppendPrintable", randchars.PrintableCharset, func(n int) []byte { return g.AppendPrintable(nil, n) }},
+		{"FillPrintable", randchars.PrintableCharset, fill(g.FillPrintable)},
+		{"ShellSafe", randchars.ShellSafeCharset, g.ShellSafe},
+		{"AppendShellSafe", randchars.ShellSafeCharset, func(n int) []byte { return g.AppendShellSafe(nil, n) }},
+		{"FillShellSafe", randchars.ShellSafeCharset, fill(g.FillShellSafe)},
+		{"URLPath", randchars.URLPathCharset, g.URLPath},
+		{"AppendURLPath", randchars.URLPathCharset, func(n int) []byte { return g.AppendURLPath(nil, n) }},
+		{"FillURLPath", randchars.URLPathCharset, fill(g.FillURLPath)},
+		{"JSONSafe", randchars.JSONSafeCharset, g.JSONSafe},
+		{"AppendJSONSafe", randchars.JSONSafeCharset, func(n int) []byte { return g.AppendJSONSafe(nil, n) }},
+		{"FillJSONSafe", randchars.JSONSafeCharset, fill(g.FillJSONSafe)},
+		{"XMLAttr", randchars.XMLAttrCharset, g.XMLAttr},
+		{"AppendXMLAttr", randchars.XMLAttrCharset, func(n int) []byte { return g.AppendXMLAttr(nil, n) }},
+		{"FillXMLAttr", randchars.XMLAttrCharset, fill(g.FillXMLAttr)},
+		{"DSNPassword", randchars.DSNPasswordCharset, g.DSNPassword},
+		{"AppendDSNPassword", randchars.DSNPasswordCharset, func(n int) []byte { return g.AppendDSNPassword(nil, n) }},
+		{"FillDSNPassword", randchars.DSNPasswordCharset, fill(g.FillDSNPassword)},
+		{"Base32", randchars.Base32Charset, g.Base32},
+		{"AppendBase32", randchars.Base32Charset, func(n int) []byte { return g.AppendBase32(nil, n) }},
+		{"FillBase32", randchars.Base32Charset, fill(g.FillBase32)},
+		{"Base32Hex", randchars.Base32HexCharset, g.Base32Hex},
+		{"AppendBase32Hex", randchars.Base32HexCharset, func(n int) []byte { return g.AppendBase32Hex(nil, n) }},
+		{"FillBase32Hex", randchars.Base32HexCharset, fill(g.FillBase32Hex)},
+		{"Crockford", randchars.CrockfordCharset, g.Crockford},
+		{"AppendCrockford", randchars.CrockfordCharset, func(n int) []byte { return g.AppendCrockford(nil, n) }},
+		{"FillCrockford", randchars.CrockfordCharset, fill(g.FillCrockford)},
+		{"Base58", randchars.Base58Charset, g.Base58},
+		{"AppendBase58", randchars.Base58Charset, func(n int) []byte { return g.AppendBase58(nil, n) }},
+		{"FillBase58", randchars.Base58Charset, fill(g.FillBase58)},
+		{"Base58Flickr", randchars.Base58FlickrCharset, g.Base58Flickr},
+		{"AppendBase58Flickr", randchars.Base58FlickrCharset, func(n int) []byte { return g.AppendBase58Flickr(nil, n) }},
+		{"FillBase58Flickr", randchars.Base58FlickrCharset, fill(g.FillBase58Flickr)},
+		// the package funcs
+		{"package AlphaNum", randchars.AlphaNumCharset, AlphaNum},
+		{"package Alpha", randchars.AlphaCharset, Alpha},
+		{"package LowerAlphaNum", randchars.LowerAlphaNumCharset, LowerAlphaNum},
+		{"package LowerAlpha", randchars.LowerAlphaCharset, LowerAlpha},
+		{"package UpperAlphaNum", randchars.UpperAlphaNumCharset, UpperAlphaNum},
+		{"package UpperAlpha", randchars.UpperAlphaCharset, UpperAlpha},
+		{"package Base64", randchars.Base64Charset, Base64},
+		{"package Base64URL", randchars.Base64URLCharset, Base64URL},
+		{"package Digits", randchars.DigitsCharset, Digits},
+		{"package LowerHex", randchars.LowerHexCharset, LowerHex},
+		{"package UpperHex", randchars.UpperHexCharset, UpperHex},
+		{"package CharsNoLeadingZero", randchars.UpperHexCharset, func(n int) []byte { return CharsNoLeadingZero(randchars.UpperHexCharset, n) }},
+		{"package Z85", randchars.Z85Charset, Z85},
+		{"package Ascii85", randchars.Ascii85Charset, Ascii85},
+		{"package Printable", randchars.PrintableCharset, Printable},
+		{"package ShellSafe", randchars.ShellSafeCharset, ShellSafe},
+		{"package URLPath", randchars.URLPathCharset, URLPath},
+		{"package JSONSafe", randchars.JSONSafeCharset, JSONSafe},
+		{"package XMLAttr", randchars.XMLAttrCharset, XMLAttr},
+		{"package DSNPassword", randchars.DSNPasswordCharset, DSNPassword},
+		{"package Base32", randchars.Base32Charset, Base32},
+		{"package Base32Hex", randchars.Base32HexCharset, Base32Hex},
+		{"package Crockford", randchars.CrockfordCharset, Crockford},
+		{"package Base58", randchars.Base58Charset, Base58},
+		{"package Base58Flickr", randchars.Base58FlickrCharset, Base58Flickr},
+		{"package Chars", randchars.DigitsCharset, func(n int) []byte { return Chars(randchars.DigitsCharset, n) }},
+		{"package Generate", randchars.DigitsCharset, func(n int) []byte { b, _ := Generate(randchars.DigitsCharset, n); return b }},
+	}
+	for _, test := range tests {
+		for _, n := range []int{0, 1, 9, 10, 11, 1000} {
+			b := test.gen(n)
+			if len(b) != n {
+				t.Errorf("%s(%d): got %d characters", test.name, n, len(b))
+			}
+			for _, c := range b {
+				if !test.cs.Contains(c) {
+					t.Errorf("%s(%d): %q is not in %q", test.name, n, c, test.cs)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	// the generic funcs accept a crandchars Generator
+	g := New()
+	s := []string{"a", "b", "c", "d"}
+	if v := randchars.Choice(g, s); !strings.Contains("abcd", v) {
+		t.Errorf("Choice: got %q", v)
+	}
+	got := randchars.Sample(g, s, 4)
+	randchars.Shuffle(g, got)
+	slices.Sort(got)
+	if !slices.Equal(got, s) {
+		t.Errorf("Sample: got %v; want a permutation of %v", got, s)
+	}
+}
+
+func TestCrockfordWithCheck(t *testing.T) {
+	for n := 1; n < 20; n++ {
+		for _, b := range [][]byte{New().CrockfordWithCheck(n), CrockfordWithCheck(n)} {
+			if len(b) != n+1 {
+				t.Errorf("%d: got %d characters; want %d", n, len(b), n+1)
+			}
+			if err := randchars.ValidateCrockford(string(b)); err != nil {
+				t.Errorf("%q: unexpected error: %s", b, err)
+			}
+		}
+	}
+}
+
+func TestBase58Check(t *testing.T) {
+	for _, n := range []int{0, 1, 16, 32} {
+		for _, b := range [][]byte{New().Base58Check(n), Base58Check(n)} {
+			p, err := randchars.ValidateBase58Check(string(b))
+			if err != nil {
+				t.Errorf("%q: unexpected error: %s", b, err)
+				continue
+			}
+			if len(p) != n {
+				t.Errorf("%q: got a %d byte payload; want %d", b, len(p), n)
+			}
+		}
+	}
+}
+
+func TestNoLeadingZero(t *testing.T) {
+	g := New()
+	for i := 0; i < 1000; i++ {
+		if b := g.CharsNoLeadingZero(randchars.DigitsCharset, 6); b[0] == '0' {
+			t.Fatalf("got %q; want no leading 0", b)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package randchars
+
+import (
+	"errors"
+	"fmt"
+)
+
+// crockfordCheck are the additional check symbols, for the values 32 to 36,
+// of Crockford's Base32.
+const crockfordCheck = "*~$=U"
+
+// ErrInvalidCrockford is returned when a string isn't valid Crockford Base32
+// or its check symbol doesn't match.
+var ErrInvalidCrockford = errors.New("randchars: invalid Crockford Base32")
+
+// crockfordValue returns the value of the Crockford Base32 symbol c, or -1 if
+// c isn't a symbol. Decoding is case-insensitive, I and L decode as 1 and O
+// decodes as 0.
+func crockfordValue(c byte) int {
+	if 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'I', 'L':
+		return 1
+	case 'O':
+		return 0
+	}
+	for i := 0; i < len(crockford); i++ {
+		if crockford[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// CrockfordCheckSymbol returns the check symbol for s, the Crockford Base32
+// encoding of a number: the number mod 37, encoded using the Crockford Base32
+// symbols and the check symbols "*~$=U". Hyphens, which may be used to make s
+// easier to read, are ignored. An error wrapping ErrInvalidCrockford is
+// returned if s has no symbols or has characters that aren't symbols.
+func CrockfordCheckSymbol(s string) (byte, error) {
+	var mod, n int
+	for i := 0; i < len(s); i++ {
+		if s[i] == '-' {
+			continue
+		}
+		v := crockfordValue(s[i])
+		if v < 0 {
+			return 0, fmt.Errorf("%w: %q: not a symbol", ErrInvalidCrockford, s[i])
+		}
+		mod = (mod*32 + v) % 37
+		n++
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("%w: no symbols", ErrInvalidCrockford)
+	}
+	if mod < 32 {
+		return crockford[mod], nil
+	}
+	return crockfordCheck[mod-32], nil
+}
+
+// ValidateCrockford validates s, Crockford Base32 followed by its check
+// symbol, as generated by CrockfordWithCheck. Like CrockfordCheckSymbol, it is
+// case-insensitive and hyphens are ignored. An error wrapping
+// ErrInvalidCrockford is returned if s is invalid or the check symbol doesn't
+// match.
+func ValidateCrockford(s string) error {
+	if len(s) == 0 {
+		return fmt.Errorf("%w: no symbols", ErrInvalidCrockford)
+	}
+	check := s[len(s)-1]
+	if 'a' <= check && check <= 'z' {
+		check -= 'a' - 'A'
+	}
+	expected, err := CrockfordCheckSymbol(s[:len(s)-1])
+	if err != nil {
+		return err
+	}
+	// the check symbol decodes like the other symbols
+	if v := crockfordValue(check); v >= 0 {
+		check = crockford[v]
+	}
+	if check != expected {
+		return fmt.Errorf("%w: %q: got check symbol %q; want %q", ErrInvalidCrockford, s, check, expected)
+	}
+	return nil
+}
+
+// CrockfordWithCheck returns n randomly generated characters using
+// Crockford's Base32 followed by their check symbol; see ValidateCrockford.
+// This will panic if n < 1.
+func (g *Generator) CrockfordWithCheck(n int) []byte {
+	if n < 1 {
+		panic(fmt.Sprintf("%d: value out of bounds", n))
+	}
+	return AppendCrockfordCheck(g.AppendCrockford(make([]byte, 0, n+1), n))
+}
+
+// AppendCrockfordCheck appends the check symbol of the Crockford Base32
+// encoded number in b to b and returns the extended slice. This will panic if
+// b isn't valid Crockford Base32; see CrockfordCheckSymbol.
+func AppendCrockfordCheck(b []byte) []byte {
+	c, err := CrockfordCheckSymbol(string(b))
+	if err != nil {
+		panic(err)
+	}
+	return append(b, c)
+}
@@ -0,0 +1,96 @@
+package randchars
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCrockfordCheckSymbol(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected byte
+		err      string
+	}{
+		{"0", '0', ""},
+		{"Z", 'Z', ""},
+		{"10", '*', ""},
+		{"11", '~', ""},
+		{"12", '$', ""},
+		{"13", '=', ""},
+		{"14", 'U', ""},
+		{"15", '0', ""},
+		{"1-4", 'U', ""},
+		{"i4", 'U', ""},
+		{"l-o", '*', ""},
+		{"", 0, "randchars: invalid Crockford Base32: no symbols"},
+		{"--", 0, "randchars: invalid Crockford Base32: no symbols"},
+		{"U1", 0, "randchars: invalid Crockford Base32: 'U': not a symbol"},
+	}
+	for _, test := range tests {
+		c, err := CrockfordCheckSymbol(test.s)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%q: got %q; want %q", test.s, err, test.err)
+			}
+			if !errors.Is(err, ErrInvalidCrockford) {
+				t.Errorf("%q: got %v; want %v", test.s, err, ErrInvalidCrockford)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%q: got no error; want %q", test.s, test.err)
+			continue
+		}
+		if c != test.expected {
+			t.Errorf("%q: got %q; want %q", test.s, c, test.expected)
+		}
+	}
+}
+
+func TestValidateCrockford(t *testing.T) {
+	for _, s := range []string{"14U", "14u", "1-4-U", "10*", "ABCD-EFGH" + string(mustCheck("ABCDEFGH"))} {
+		if err := ValidateCrockford(s); err != nil {
+			t.Errorf("%q: unexpected error: %s", s, err)
+		}
+	}
+	for _, s := range []string{"", "U", "14*", "14", "U14"} {
+		if err := ValidateCrockford(s); !errors.Is(err, ErrInvalidCrockford) {
+			t.Errorf("%q: got %v; want %v", s, err, ErrInvalidCrockford)
+		}
+	}
+	if b := CrockfordWithCheck(8); ValidateCrockford(string(b)) != nil {
+		t.Errorf("%q: the package func's check symbol is invalid", b)
+	}
+	// the check symbol catches any single substitution
+	g := NewGeneratorWithSeed(0)
+	for n := 1; n < 20; n++ {
+		b := g.CrockfordWithCheck(n)
+		if len(b) != n+1 {
+			t.Fatalf("%d: got %d characters; want %d", n, len(b), n+1)
+		}
+		if err := ValidateCrockford(string(b)); err != nil {
+			t.Fatalf("%q: unexpected error: %s", b, err)
+		}
+		for i := 0; i < n; i++ {
+			c := b[i]
+			for j := 0; j < len(crockford); j++ {
+				if crockford[j] == c {
+					continue
+				}
+				b[i] = crockford[j]
+				if ValidateCrockford(string(b)) == nil {
+					t.Errorf("%q: substitution at %d wasn't detected", b, i)
+				}
+			}
+			b[i] = c
+		}
+	}
+}
+
+func mustCheck(s string) byte {
+	c, err := CrockfordCheckSymbol(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,34 @@
+package randchars
+
+import "math"
+
+// Entropy returns the entropy, in bits, of n characters that are each chosen
+// uniformly at random from a set of size characters: n * log2(size).
+func Entropy(size, n int) float64 {
+	if size <= 0 || n <= 0 {
+		return 0
+	}
+	return float64(n) * math.Log2(float64(size))
+}
+
+// MinLength returns the minimum number of characters, each chosen uniformly
+// at random from a set of size characters, needed for at least bits of
+// entropy. If bits is <= 0, 0 is returned. If no number of characters
+// suffices, i.e. size < 2, -1 is returned.
+func MinLength(size int, bits float64) int {
+	if bits <= 0 {
+		return 0
+	}
+	if size < 2 || math.IsInf(bits, 1) || math.IsNaN(bits) {
+		return -1
+	}
+	n := int(math.Ceil(bits / math.Log2(float64(size))))
+	// correct for rounding in the division
+	for n > 0 && Entropy(size, n-1) >= bits {
+		n--
+	}
+	for Entropy(size, n) < bits {
+		n++
+	}
+	return n
+}
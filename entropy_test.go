@@ -0,0 +1,81 @@
+package randchars
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEntropy(t *testing.T) {
+	tests := []struct {
+		size, n  int
+		expected float64
+	}{
+		{64, 22, 132},
+		{16, 32, 128},
+		{62, 10, 10 * math.Log2(62)},
+		{1, 10, 0},
+		{0, 10, 0},
+		{10, 0, 0},
+	}
+	for _, test := range tests {
+		if e := Entropy(test.size, test.n); math.Abs(e-test.expected) > 1e-9 {
+			t.Errorf("%d, %d: got %f; want %f", test.size, test.n, e, test.expected)
+		}
+	}
+	if e := Base64Charset.Entropy(16); e != 96 {
+		t.Errorf("Base64Charset: got %f; want 96", e)
+	}
+	if e := MustRuneCharset("αβγδ").Entropy(8); e != 16 {
+		t.Errorf("RuneCharset: got %f; want 16", e)
+	}
+}
+
+func TestMinLength(t *testing.T) {
+	tests := []struct {
+		size     int
+		bits     float64
+		expected int
+	}{
+		{64, 128, 22},
+		{64, 126, 21},
+		{16, 128, 32},
+		{2, 128, 128},
+		{62, 128, 22},
+		{10, 20, 7},
+		{26, 0.1, 1},
+		{26, 0, 0},
+		{26, -1, 0},
+		{1, 8, -1},
+		{0, 8, -1},
+		{62, math.Inf(1), -1},
+	}
+	for _, test := range tests {
+		n := MinLength(test.size, test.bits)
+		if n != test.expected {
+			t.Errorf("%d, %.1f: got %d; want %d", test.size, test.bits, n, test.expected)
+		}
+	}
+	// the minimum length is the first that has at least the requested bits
+	for _, cs := range []Charset{AlphaNumCharset, DigitsCharset, Base64Charset, UnambiguousAlphaNumCharset} {
+		for bits := 1.0; bits <= 256; bits++ {
+			n := cs.MinLength(bits)
+			if cs.Entropy(n) < bits || cs.Entropy(n-1) >= bits {
+				t.Errorf("%s: %.0f bits: got %d", cs, bits, n)
+			}
+		}
+	}
+	if n := MustRuneCharset("αβγδ").MinLength(15); n != 8 {
+		t.Errorf("RuneCharset: got %d; want 8", n)
+	}
+}
+
+func TestTemplateEntropy(t *testing.T) {
+	tmpl := MustCompileTemplate(`SKU-{A3}-999-x`)
+	expected := 3*math.Log2(26) + 3*math.Log2(10) + math.Log2(62)
+	if e := tmpl.Entropy(); math.Abs(e-expected) > 1e-9 {
+		t.Errorf("got %f; want %f", e, expected)
+	}
+	if e := MustCompileTemplate(`\A\9`).Entropy(); e != 0 {
+		t.Errorf("literal only: got %f; want 0", e)
+	}
+}
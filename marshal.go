@@ -0,0 +1,321 @@
+package randchars
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+)
+
+// The marshaled form of a generator is a version byte followed by its
+// Source's marshaled form, which is a kind byte followed by the Source's
+// state. The version changes when the format does.
+const marshalVersion = 1
+
+// Source kinds in the marshaled form. kindCustom is a Source that isn't
+// provided by this package but implements encoding.BinaryMarshaler.
+const (
+	kindCustom byte = iota
+	kindPCG
+	kindXoroshiro
+	kindXoshiro256
+	kindWyRand
+	kindChaCha8
+)
+
+// ErrInvalidState is returned when a marshaled generator or Source can't be
+// unmarshaled.
+var ErrInvalidState = errors.New("randchars: invalid generator state")
+
+// marshalUint64s returns kind followed by v in little-endian order.
+func marshalUint64s(kind byte, v ...uint64) []byte {
+	b := make([]byte, 1, 1+8*len(v))
+	b[0] = kind
+	for _, u := range v {
+		b = binary.LittleEndian.AppendUint64(b, u)
+	}
+	return b
+}
+
+// unmarshalUint64s reads len(v) uint64s, marshaled with marshalUint64s, from
+// b into v.
+func unmarshalUint64s(b []byte, kind byte, v ...*uint64) error {
+	if len(b) != 1+8*len(v) || b[0] != kind {
+		return ErrInvalidState
+	}
+	for i, u := range v {
+		*u = binary.LittleEndian.Uint64(b[1+8*i:])
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s *PCGSource) MarshalBinary() ([]byte, error) {
+	return marshalUint64s(kindPCG, s.rng.State, s.rng.Inc), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. PCG's increment
+// must be odd.
+func (s *PCGSource) UnmarshalBinary(b []byte) error {
+	var state, inc uint64
+	if err := unmarshalUint64s(b, kindPCG, &state, &inc); err != nil {
+		return err
+	}
+	if inc&1 == 0 {
+		return fmt.Errorf("%w: PCG increment %#x is even", ErrInvalidState, inc)
+	}
+	s.rng.State, s.rng.Inc = state, inc
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s *XoroshiroSource) MarshalBinary() ([]byte, error) {
+	return marshalUint64s(kindXoroshiro, s.rng[0], s.rng[1]), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The state can't be
+// all zeros; xoroshiro would only generate zeros.
+func (s *XoroshiroSource) UnmarshalBinary(b []byte) error {
+	var s0, s1 uint64
+	if err := unmarshalUint64s(b, kindXoroshiro, &s0, &s1); err != nil {
+		return err
+	}
+	if s0|s1 == 0 {
+		return fmt.Errorf("%w: xoroshiro state is all zeros", ErrInvalidState)
+	}
+	s.rng[0], s.rng[1] = s0, s1
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s *Xoshiro256Source) MarshalBinary() ([]byte, error) {
+	return marshalUint64s(kindXoshiro256, s.s[:]...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The state can't be
+// all zeros; xoshiro256** would only generate zeros.
+func (s *Xoshiro256Source) UnmarshalBinary(b []byte) error {
+	var t [4]uint64
+	if err := unmarshalUint64s(b, kindXoshiro256, &t[0], &t[1], &t[2], &t[3]); err != nil {
+		return err
+	}
+	if t[0]|t[1]|t[2]|t[3] == 0 {
+		return fmt.Errorf("%w: xoshiro256** state is all zeros", ErrInvalidState)
+	}
+	s.s = t
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s *WyRandSource) MarshalBinary() ([]byte, error) {
+	return marshalUint64s(kindWyRand, s.state), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *WyRandSource) UnmarshalBinary(b []byte) error {
+	return unmarshalUint64s(b, kindWyRand, &s.state)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s *ChaCha8Source) MarshalBinary() ([]byte, error) {
+	b, err := s.rng.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{kindChaCha8}, b...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *ChaCha8Source) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 || b[0] != kindChaCha8 {
+		return ErrInvalidState
+	}
+	rng := new(rand.ChaCha8)
+	if err := rng.UnmarshalBinary(b[1:]); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidState, err)
+	}
+	s.rng = rng
+	return nil
+}
+
+// marshalSource returns the marshaled form of a generator using src.
+func marshalSource(src Source) ([]byte, error) {
+	b := []byte{marshalVersion}
+	switch src := src.(type) {
+	case nil:
+		return nil, errors.New("randchars: generator has no Source")
+	case *PCGSource, *XoroshiroSource, *Xoshiro256Source, *WyRandSource, *ChaCha8Source:
+		s, err := src.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return append(b, s...), nil
+	case encoding.BinaryMarshaler:
+		s, err := src.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return append(append(b, kindCustom), s...), nil
+	}
+	return nil, fmt.Errorf("randchars: %T: Source can't be marshaled", src)
+}
+
+// unmarshalSource returns the Source in the marshaled form of a generator, b.
+// The Source is created from its kind, except for a custom Source, which is
+// unmarshaled into src.
+func unmarshalSource(src Source, b []byte) (Source, error) {
+	if len(b) < 2 {
+		return nil, ErrInvalidState
+	}
+	if b[0] != marshalVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidState, b[0])
+	}
+	var s interface {
+		Source
+		encoding.BinaryUnmarshaler
+	}
+	switch b[1] {
+	case kindPCG:
+		s = new(PCGSource)
+	case kindXoroshiro:
+		s = new(XoroshiroSource)
+	case kindXoshiro256:
+		s = new(Xoshiro256Source)
+	case kindWyRand:
+		s = new(WyRandSource)
+	case kindChaCha8:
+		s = new(ChaCha8Source)
+	case kindCustom:
+		u, ok := src.(encoding.BinaryUnmarshaler)
+		if !ok {
+			return nil, fmt.Errorf("%w: a custom Source needs a Source that implements encoding.BinaryUnmarshaler", ErrInvalidState)
+		}
+		if err := u.UnmarshalBinary(b[2:]); err != nil {
+			return nil, err
+		}
+		return src, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown Source kind %d", ErrInvalidState, b[1])
+	}
+	if err := s.UnmarshalBinary(b[1:]); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// marshalText returns the hex encoding of b.
+func marshalText(b []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return hex.AppendEncode(nil, b), nil
+}
+
+// unmarshalText returns the hex decoding of text.
+func unmarshalText(text []byte) ([]byte, error) {
+	b, err := hex.AppendDecode(nil, text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidState, err)
+	}
+	return b, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler: the Generator's state
+// is marshaled, so a Generator that unmarshals it continues with the same
+// output. An error is returned if the Generator's Source can't be marshaled;
+// the provided Sources can be.
+func (g *Generator) MarshalBinary() ([]byte, error) {
+	return marshalSource(g.src)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler; the Generator's
+// Source is replaced by the marshaled one, so a zero Generator can be used.
+// An error wrapping ErrInvalidState is returned if b isn't a marshaled
+// Generator.
+func (g *Generator) UnmarshalBinary(b []byte) error {
+	src, err := unmarshalSource(g.src, b)
+	if err != nil {
+		return err
+	}
+	g.src = src
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler; the text is the hex
+// encoding of MarshalBinary's output.
+func (g *Generator) MarshalText() ([]byte, error) {
+	return marshalText(g.MarshalBinary())
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (g *Generator) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	return g.UnmarshalBinary(b)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler; see
+// Generator.MarshalBinary.
+func (g *Base64Generator) MarshalBinary() ([]byte, error) {
+	return marshalSource(g.src)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler; see
+// Generator.UnmarshalBinary.
+func (g *Base64Generator) UnmarshalBinary(b []byte) error {
+	src, err := unmarshalSource(g.src, b)
+	if err != nil {
+		return err
+	}
+	g.src = src
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (g *Base64Generator) MarshalText() ([]byte, error) {
+	return marshalText(g.MarshalBinary())
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (g *Base64Generator) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	return g.UnmarshalBinary(b)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler; see
+// Generator.MarshalBinary.
+func (g *Base64URLGenerator) MarshalBinary() ([]byte, error) {
+	return marshalSource(g.src)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler; see
+// Generator.UnmarshalBinary.
+func (g *Base64URLGenerator) UnmarshalBinary(b []byte) error {
+	src, err := unmarshalSource(g.src, b)
+	if err != nil {
+		return err
+	}
+	g.src = src
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (g *Base64URLGenerator) MarshalText() ([]byte, error) {
+	return marshalText(g.MarshalBinary())
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (g *Base64URLGenerator) UnmarshalText(text []byte) error {
+	b, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+	return g.UnmarshalBinary(b)
+}
@@ -0,0 +1,140 @@
+package randchars
+
+import (
+	"encoding"
+	"errors"
+	"testing"
+)
+
+func TestGeneratorMarshal(t *testing.T) {
+	for _, test := range sources(42) {
+		g := NewGeneratorWithSource(test.src)
+		g.AlphaNum(37)
+		b, err := g.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		text, err := g.MarshalText()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		expected := string(g.AlphaNum(100)) + string(g.Base64(100))
+		var g2, g3 Generator
+		if err := g2.UnmarshalBinary(b); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		if err := g3.UnmarshalText(text); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		for _, g := range []*Generator{&g2, &g3} {
+			if s := string(g.AlphaNum(100)) + string(g.Base64(100)); s != expected {
+				t.Errorf("%s: got %q after restoring; want %q", test.name, s, expected)
+			}
+		}
+	}
+}
+
+func TestBase64GeneratorMarshal(t *testing.T) {
+	gens := []struct {
+		name string
+		g    interface {
+			Bytes(int) []byte
+			encoding.BinaryMarshaler
+			encoding.TextMarshaler
+		}
+		restored interface {
+			Bytes(int) []byte
+			encoding.BinaryUnmarshaler
+			encoding.TextUnmarshaler
+		}
+	}{
+		{"Base64", NewBase64GeneratorWithSeed(42), &Base64Generator{}},
+		{"Base64URL", NewBase64URLGeneratorWithSeed(42), &Base64URLGenerator{}},
+		{"Base64 ChaCha8", NewBase64GeneratorWithSource(NewChaCha8Source(42)), &Base64Generator{}},
+	}
+	for _, test := range gens {
+		test.g.Bytes(13)
+		text, err := test.g.MarshalText()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		expected := string(test.g.Bytes(100))
+		if err := test.restored.UnmarshalText(text); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		if s := string(test.restored.Bytes(100)); s != expected {
+			t.Errorf("%s: got %q after restoring; want %q", test.name, s, expected)
+		}
+	}
+}
+
+// customSource is a Source that isn't provided by the package.
+type customSource struct {
+	WyRandSource
+}
+
+func (s *customSource) MarshalBinary() ([]byte, error) {
+	return []byte{byte(s.state)}, nil
+}
+
+func (s *customSource) UnmarshalBinary(b []byte) error {
+	s.state = uint64(b[0])
+	return nil
+}
+
+func TestGeneratorMarshalCustom(t *testing.T) {
+	g := NewGeneratorWithSource(&customSource{WyRandSource{7}})
+	b, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := string(g.AlphaNum(20))
+	restored := NewGeneratorWithSource(&customSource{})
+	if err := restored.UnmarshalBinary(b); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := string(restored.AlphaNum(20)); s != expected {
+		t.Errorf("got %q after restoring; want %q", s, expected)
+	}
+	// a custom Source needs a Source to unmarshal into
+	var zero Generator
+	if err := zero.UnmarshalBinary(b); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("got %v; want %v", err, ErrInvalidState)
+	}
+	// a Source that can't be marshaled
+	g = NewGeneratorWithSource(struct{ Source }{NewWyRandSource(0)})
+	if _, err := g.MarshalBinary(); err == nil {
+		t.Error("got no error for a Source that can't be marshaled")
+	}
+}
+
+func TestGeneratorUnmarshalInvalid(t *testing.T) {
+	good, _ := NewGeneratorWithSeed(0).MarshalBinary()
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{"empty", nil},
+		{"version", append([]byte{2}, good[1:]...)},
+		{"kind", append([]byte{1, 99}, good[2:]...)},
+		{"truncated", good[:len(good)-1]},
+		{"long", append(good, 0)},
+		{"chacha8", []byte{1, kindChaCha8, 'x'}},
+		{"pcg even increment", append([]byte{1, kindPCG}, make([]byte, 16)...)},
+		{"xoroshiro zeros", append([]byte{1, kindXoroshiro}, make([]byte, 16)...)},
+		{"xoshiro256 zeros", append([]byte{1, kindXoshiro256}, make([]byte, 32)...)},
+	}
+	for _, test := range tests {
+		var g Generator
+		if err := g.UnmarshalBinary(test.b); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("%s: got %v; want %v", test.name, err, ErrInvalidState)
+		}
+	}
+	var g Generator
+	if err := g.UnmarshalText([]byte("zz")); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("text: got %v; want %v", err, ErrInvalidState)
+	}
+	if _, err := g.MarshalBinary(); err == nil {
+		t.Error("zero Generator: got no error")
+	}
+}
@@ -0,0 +1,259 @@
+// Package passphrase generates diceware style passphrases, e.g.
+// "correct-horse-battery-staple": words chosen at random from a Wordlist.
+//
+// The EFF's large wordlist, 7776 words, and its second short wordlist, 1296
+// words with unique three character prefixes, are embedded; see
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases.
+// Custom wordlists can be read from a file.
+//
+// Words, injected digits and symbols, and where they are injected are chosen
+// with a randchars.IntNer. Use a crandchars.Generator for passphrases that
+// protect anything; a seeded randchars.Generator is only for reproducible
+// output, e.g. in tests.
+package passphrase
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mohae/randchars"
+)
+
+const (
+	digits = "0123456789"
+	// DefaultSymbols are the symbols injected when a Policy doesn't specify
+	// any.
+	DefaultSymbols = "!#$%&*+=?@^"
+	// MaxWords is the maximum number of words supported.
+	MaxWords = 256
+)
+
+var (
+	//go:embed eff_large_wordlist.txt
+	effLarge string
+	//go:embed eff_short_wordlist_2_0.txt
+	effShort string
+
+	// EFFLarge is the EFF's large wordlist: 7776 words, each adds 12.9 bits
+	// of entropy.
+	EFFLarge = mustWordlist(effLarge)
+	// EFFShort is the EFF's second short wordlist: 1296 words, each adds 10.3
+	// bits of entropy. The words are longer than those in the first short
+	// wordlist but each has a unique three character prefix, which allows for
+	// autocompletion.
+	EFFShort = mustWordlist(effShort)
+)
+
+// ErrEmptyWordlist is returned when a Wordlist has no words.
+var ErrEmptyWordlist = errors.New("passphrase: wordlist has no words")
+
+// Wordlist is a list of unique words from which passphrases are made.
+//
+// The zero value is an empty Wordlist.
+type Wordlist struct {
+	words []string
+}
+
+// NewWordlist returns a Wordlist made up of words. An error is returned if
+// there are no words, a word is empty or contains white space, or a word
+// occurs more than once.
+func NewWordlist(words []string) (Wordlist, error) {
+	if len(words) == 0 {
+		return Wordlist{}, ErrEmptyWordlist
+	}
+	seen := make(map[string]bool, len(words))
+	for i, w := range words {
+		if w == "" || strings.IndexFunc(w, unicode.IsSpace) >= 0 || !utf8.ValidString(w) {
+			return Wordlist{}, fmt.Errorf("passphrase: %d: %q: not a valid word", i, w)
+		}
+		if seen[w] {
+			return Wordlist{}, fmt.Errorf("passphrase: %q: duplicate word", w)
+		}
+		seen[w] = true
+	}
+	return Wordlist{words: words}, nil
+}
+
+// ReadWordlist returns a Wordlist read from r. Each non-empty line is either
+// a word or, as in diceware wordlists, the dice roll for a word followed by
+// the word, e.g. "11111	abacus".
+func ReadWordlist(r io.Reader) (Wordlist, error) {
+	var words []string
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		fields := strings.Fields(s.Text())
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			words = append(words, fields[0])
+		case 2:
+			if strings.Trim(fields[0], "123456") != "" {
+				return Wordlist{}, fmt.Errorf("passphrase: line %d: %q: not a dice roll", n, fields[0])
+			}
+			words = append(words, fields[1])
+		default:
+			return Wordlist{}, fmt.Errorf("passphrase: line %d: %q: expected a word or a dice roll and a word", n, s.Text())
+		}
+	}
+	if err := s.Err(); err != nil {
+		return Wordlist{}, fmt.Errorf("passphrase: %w", err)
+	}
+	return NewWordlist(words)
+}
+
+// LoadWordlist returns the Wordlist in the named file; see ReadWordlist for
+// the format.
+func LoadWordlist(name string) (Wordlist, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return Wordlist{}, fmt.Errorf("passphrase: %w", err)
+	}
+	defer f.Close()
+	return ReadWordlist(f)
+}
+
+func mustWordlist(s string) Wordlist {
+	wl, err := ReadWordlist(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return wl
+}
+
+// Len returns the number of words in the Wordlist.
+func (wl Wordlist) Len() int {
+	return len(wl.words)
+}
+
+// Word returns the i'th word in the Wordlist.
+func (wl Wordlist) Word(i int) string {
+	return wl.words[i]
+}
+
+// Policy defines the passphrases to generate.
+type Policy struct {
+	// Words is the number of words in the passphrase.
+	Words int
+	// Wordlist is the Wordlist the words are chosen from; if it is empty,
+	// EFFLarge is used.
+	Wordlist Wordlist
+	// Separator is placed between the words, e.g. "-". It may be empty.
+	Separator string
+	// Capitalize capitalizes the first letter of each word.
+	Capitalize bool
+	// Digits is the number of random digits to inject.
+	Digits int
+	// Symbols is the number of random symbols to inject.
+	Symbols int
+	// SymbolSet is the set of symbols to inject from; if empty,
+	// DefaultSymbols is used. It may not contain letters or digits.
+	SymbolSet string
+}
+
+// token kinds
+const (
+	word = iota
+	digit
+	symbol
+)
+
+// Generator generates passphrases that satisfy a Policy. A Generator is not
+// safe for concurrent use unless its randchars.IntNer is.
+type Generator struct {
+	r       randchars.IntNer
+	p       Policy
+	symbols string
+	// tokens are the kinds of the passphrase's tokens; they are shuffled to
+	// place the digits and symbols.
+	tokens []int
+}
+
+// New returns a Generator that uses r to generate passphrases satisfying p.
+// An error is returned if p is invalid.
+func New(r randchars.IntNer, p Policy) (*Generator, error) {
+	if p.Words <= 0 || p.Words > MaxWords {
+		return nil, fmt.Errorf("passphrase: %d: words must be between 1 and %d", p.Words, MaxWords)
+	}
+	if p.Digits < 0 {
+		return nil, fmt.Errorf("passphrase: %d: digits must be >= 0", p.Digits)
+	}
+	if p.Symbols < 0 {
+		return nil, fmt.Errorf("passphrase: %d: symbols must be >= 0", p.Symbols)
+	}
+	if p.Wordlist.Len() == 0 {
+		p.Wordlist = EFFLarge
+	}
+	g := &Generator{r: r, p: p, symbols: DefaultSymbols}
+	if p.SymbolSet != "" {
+		cs, err := randchars.NewSymbolCharset(p.SymbolSet)
+		if err != nil {
+			return nil, fmt.Errorf("passphrase: %w", err)
+		}
+		g.symbols = cs.String()
+	}
+	for _, t := range []struct{ kind, n int }{{word, p.Words}, {digit, p.Digits}, {symbol, p.Symbols}} {
+		for i := 0; i < t.n; i++ {
+			g.tokens = append(g.tokens, t.kind)
+		}
+	}
+	return g, nil
+}
+
+// Generate returns a passphrase. Each injected digit and symbol is placed at
+// a random position before, between, or after the words without a
+// separator, e.g. "correct-horse7-battery-staple".
+func (g *Generator) Generate() []byte {
+	// every arrangement of the token kinds is equally likely
+	randchars.Shuffle(g.r, g.tokens)
+	var b []byte
+	words := 0
+	for _, t := range g.tokens {
+		switch t {
+		case word:
+			if words > 0 {
+				b = append(b, g.p.Separator...)
+			}
+			words++
+			w := g.p.Wordlist.Word(g.r.IntN(g.p.Wordlist.Len()))
+			if g.p.Capitalize {
+				r, size := utf8.DecodeRuneInString(w)
+				b = utf8.AppendRune(b, unicode.ToUpper(r))
+				w = w[size:]
+			}
+			b = append(b, w...)
+		case digit:
+			b = append(b, digits[g.r.IntN(len(digits))])
+		case symbol:
+			b = append(b, g.symbols[g.r.IntN(len(g.symbols))])
+		}
+	}
+	return b
+}
+
+// Entropy returns the entropy, in bits, of the random choices made to
+// generate a passphrase: the words, the injected digits and symbols, and
+// where they are injected. This is the entropy of the passphrases as long as
+// different choices always result in different passphrases, e.g. the words
+// don't contain the separator, digits, or symbols.
+func (g *Generator) Entropy() float64 {
+	w, d, s := g.p.Words, g.p.Digits, g.p.Symbols
+	// log2 of the (w+d+s)!/(w!d!s!) arrangements of the tokens
+	lg := func(n int) float64 {
+		v, _ := math.Lgamma(float64(n + 1))
+		return v / math.Ln2
+	}
+	e := lg(w+d+s) - lg(w) - lg(d) - lg(s)
+	e += float64(w) * math.Log2(float64(g.p.Wordlist.Len()))
+	e += float64(d) * math.Log2(float64(len(digits)))
+	e += float64(s) * math.Log2(float64(len(g.symbols)))
+	return e
+}
@@ -0,0 +1,195 @@
+package passphrase
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/mohae/randchars"
+	"github.com/mohae/randchars/crandchars"
+)
+
+func TestEmbedded(t *testing.T) {
+	tests := []struct {
+		name        string
+		wl          Wordlist
+		n           int
+		first, last string
+	}{
+		{"large", EFFLarge, 7776, "abacus", "zoom"},
+		{"short", EFFShort, 1296, "aardvark", "zucchini"},
+	}
+	for _, test := range tests {
+		if test.wl.Len() != test.n {
+			t.Errorf("%s: got %d words; want %d", test.name, test.wl.Len(), test.n)
+		}
+		if test.wl.Word(0) != test.first || test.wl.Word(test.n-1) != test.last {
+			t.Errorf("%s: got %q...%q; want %q...%q", test.name, test.wl.Word(0), test.wl.Word(test.n-1), test.first, test.last)
+		}
+	}
+	// each word in the short list has a unique 3 character prefix
+	prefixes := make(map[string]bool)
+	for i := 0; i < EFFShort.Len(); i++ {
+		p := EFFShort.Word(i)[:3]
+		if prefixes[p] {
+			t.Errorf("%q: duplicate prefix", p)
+		}
+		prefixes[p] = true
+	}
+}
+
+func TestReadWordlist(t *testing.T) {
+	tests := []struct {
+		s     string
+		words []string
+		err   string
+	}{
+		{"correct\nhorse\n\nbattery\n", []string{"correct", "horse", "battery"}, ""},
+		{"11\tcorrect\n12\thorse\n", []string{"correct", "horse"}, ""},
+		{"", nil, ErrEmptyWordlist.Error()},
+		{"17\tcorrect\n", nil, "passphrase: line 1: \"17\": not a dice roll"},
+		{"11 correct horse\n", nil, "passphrase: line 1: \"11 correct horse\": expected a word or a dice roll and a word"},
+		{"horse\nhorse\n", nil, "passphrase: \"horse\": duplicate word"},
+	}
+	for _, test := range tests {
+		wl, err := ReadWordlist(strings.NewReader(test.s))
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%q: got %q; want %q", test.s, err, test.err)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%q: got no error; want %q", test.s, test.err)
+			continue
+		}
+		if wl.Len() != len(test.words) {
+			t.Errorf("%q: got %d words; want %d", test.s, wl.Len(), len(test.words))
+			continue
+		}
+		for i, w := range test.words {
+			if wl.Word(i) != w {
+				t.Errorf("%q: word %d: got %q; want %q", test.s, i, wl.Word(i), w)
+			}
+		}
+	}
+	if _, err := NewWordlist([]string{"a b"}); err == nil {
+		t.Error("a word with white space: got no error")
+	}
+}
+
+func TestLoadWordlist(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "words.txt")
+	err := os.WriteFile(name, []byte("alpha\nbravo\ncharlie\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wl, err := LoadWordlist(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if wl.Len() != 3 {
+		t.Errorf("got %d words; want 3", wl.Len())
+	}
+	_, err = LoadWordlist(filepath.Join(t.TempDir(), "missing.txt"))
+	if err == nil {
+		t.Error("missing file: got no error")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Policy
+		err  string
+	}{
+		{"no words", Policy{}, "passphrase: 0: words must be between 1 and 256"},
+		{"too many words", Policy{Words: MaxWords + 1}, "passphrase: 257: words must be between 1 and 256"},
+		{"negative digits", Policy{Words: 4, Digits: -1}, "passphrase: -1: digits must be >= 0"},
+		{"negative symbols", Policy{Words: 4, Symbols: -1}, "passphrase: -1: symbols must be >= 0"},
+		{"bad symbols", Policy{Words: 4, Symbols: 1, SymbolSet: "é"}, "passphrase: 0xc3: not an ASCII character"},
+		{"letter symbols", Policy{Words: 4, Symbols: 1, SymbolSet: "!a"}, `passphrase: "!a": symbols may not contain letters or digits`},
+		{"digit symbols", Policy{Words: 4, Symbols: 1, SymbolSet: "7?"}, `passphrase: "7?": symbols may not contain letters or digits`},
+		{"valid", Policy{Words: 6, Separator: " ", Digits: 1, Symbols: 1}, ""},
+	}
+	for _, test := range tests {
+		_, err := New(randchars.NewGenerator(), test.p)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%s: got %q; want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%s: got no error; want %q", test.name, test.err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	wl, _ := NewWordlist([]string{"correct", "horse", "battery", "staple"})
+	p := Policy{Words: 5, Wordlist: wl, Separator: ".", Capitalize: true, Digits: 2, Symbols: 1, SymbolSet: "!"}
+	for _, r := range []randchars.IntNer{randchars.NewGeneratorWithSeed(0), crandchars.New()} {
+		g, err := New(r, p)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		for i := 0; i < 1000; i++ {
+			b := string(g.Generate())
+			var d, s int
+			for _, c := range b {
+				switch {
+				case unicode.IsDigit(c):
+					d++
+				case c == '!':
+					s++
+				}
+			}
+			if d != 2 || s != 1 {
+				t.Fatalf("%q: got %d digits and %d symbols; want 2 and 1", b, d, s)
+			}
+			words := strings.Split(strings.Trim(b, "0123456789!"), ".")
+			if len(words) != 5 {
+				t.Fatalf("%q: got %d words; want 5", b, len(words))
+			}
+			for _, w := range words {
+				w = strings.Trim(w, "0123456789!")
+				switch w {
+				case "Correct", "Horse", "Battery", "Staple":
+				default:
+					t.Fatalf("%q: %q is not a capitalized word from the list", b, w)
+				}
+			}
+		}
+	}
+	// the EFF list is used by default
+	g, _ := New(randchars.NewGeneratorWithSeed(0), Policy{Words: 6, Separator: "-"})
+	words := strings.Split(string(g.Generate()), "-")
+	if len(words) < 6 {
+		t.Fatalf("got %d words; want at least 6", len(words))
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	tests := []struct {
+		p        Policy
+		expected float64
+	}{
+		{Policy{Words: 6}, 6 * math.Log2(7776)},
+		{Policy{Words: 4, Wordlist: EFFShort, Capitalize: true}, 4 * math.Log2(1296)},
+		// 6!/(4!1!1!) arrangements
+		{Policy{Words: 4, Digits: 1, Symbols: 1, SymbolSet: "!?"}, math.Log2(30) + 4*math.Log2(7776) + math.Log2(10) + 1},
+	}
+	for _, test := range tests {
+		g, err := New(randchars.NewGenerator(), test.p)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if math.Abs(g.Entropy()-test.expected) > 1e-9 {
+			t.Errorf("%d words: got %f; want %f", test.p.Words, g.Entropy(), test.expected)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// Package password generates passwords that satisfy a Policy: a total length
+// and per-class minimums and maximums for lowercase letters, uppercase
+// letters, digits, and symbols.
+//
+// Passwords satisfy the Policy by construction; there is no generate and
+// retry. Every password that satisfies the Policy is equally likely to be
+// generated, which makes the reported entropy exact: it is log2 of the number
+// of passwords that satisfy the Policy.
+//
+// Pronounceable passwords, made up of consonant-vowel syllables that are easy
+// to say and type, are generated by a PronounceableGenerator. They are also
+// uniformly distributed so their entropy is exact too.
+//
+// The randomness comes from a randchars.IntNer; both randchars.Generator, a
+// PRNG, and crandchars.Generator, a CSPRNG, can be used. Passwords should
+// normally be generated using crandchars.
+package password
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/mohae/randchars"
+)
+
+const (
+	lower  = "abcdefghijklmnopqrstuvwxyz"
+	upper  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digits = "0123456789"
+	// DefaultSymbols are the symbols used when a Policy doesn't specify any.
+	DefaultSymbols = "!#$%&*+-=?@^_"
+	// MaxLength is the maximum password length supported.
+	MaxLength = 1024
+)
+
+// ErrUnsatisfiable is returned when no password can satisfy a Policy.
+var ErrUnsatisfiable = errors.New("password: policy cannot be satisfied")
+
+// Class is the number of characters a password must, and may, have from a
+// class of characters.
+type Class struct {
+	// Min is the minimum number of characters from the class.
+	Min int
+	// Max is the maximum number of characters from the class. 0 means there
+	// is no maximum; a negative value excludes the class.
+	Max int
+}
+
+// Policy defines the passwords to generate.
+type Policy struct {
+	// Length is the total length of the password.
+	Length  int
+	Lower   Class
+	Upper   Class
+	Digits  Class
+	Symbols Class
+	// SymbolSet is the set of allowed symbols; if empty, DefaultSymbols is
+	// used. It may not contain letters or digits.
+	SymbolSet string
+}
+
+// class is a Class that has been validated against the Policy's length.
+type class struct {
+	chars    string
+	min, max int
+}
+
+// Generator generates passwords that satisfy a Policy. A Generator is not
+// safe for concurrent use unless its randchars.IntNer is.
+type Generator struct {
+	r       randchars.IntNer
+	length  int
+	classes []class
+	// ways[j][t] is the number of strings of length t that satisfy the
+	// constraints of classes[:j].
+	ways [][]*big.Int
+}
+
+// New returns a Generator that uses r to generate passwords satisfying p. An
+// error is returned if p is invalid or cannot be satisfied.
+func New(r randchars.IntNer, p Policy) (*Generator, error) {
+	if p.Length <= 0 || p.Length > MaxLength {
+		return nil, fmt.Errorf("password: %d: length must be between 1 and %d", p.Length, MaxLength)
+	}
+	symbols, err := symbolSet(p.SymbolSet)
+	if err != nil {
+		return nil, err
+	}
+	g := &Generator{r: r, length: p.Length}
+	var min, max int
+	for _, c := range []struct {
+		name  string
+		chars string
+		Class
+	}{
+		{"lower", lower, p.Lower},
+		{"upper", upper, p.Upper},
+		{"digits", digits, p.Digits},
+		{"symbols", symbols, p.Symbols},
+	} {
+		if c.Max < 0 {
+			if c.Min > 0 {
+				return nil, fmt.Errorf("password: %s: minimum of %d for an excluded class", c.name, c.Min)
+			}
+			continue
+		}
+		if c.Min < 0 {
+			return nil, fmt.Errorf("password: %s: %d: minimum must be >= 0", c.name, c.Min)
+		}
+		if c.Max == 0 || c.Max > p.Length {
+			c.Max = p.Length
+		}
+		if c.Min > c.Max {
+			return nil, fmt.Errorf("password: %s: minimum of %d is greater than maximum of %d", c.name, c.Min, c.Max)
+		}
+		g.classes = append(g.classes, class{chars: c.chars, min: c.Min, max: c.Max})
+		min += c.Min
+		max += c.Max
+	}
+	if min > p.Length || max < p.Length {
+		return nil, ErrUnsatisfiable
+	}
+	g.count()
+	return g, nil
+}
+
+// MinLength returns the minimum Length for which the passwords satisfying p
+// have at least bits of entropy; p.Length is ignored. An error is returned if
+// p is invalid or no length up to MaxLength suffices.
+func MinLength(p Policy, bits float64) (int, error) {
+	// no password is shorter than the sum of the minimums
+	start := 1
+	if min := p.Lower.Min + p.Upper.Min + p.Digits.Min + p.Symbols.Min; min > start {
+		start = min
+	}
+	for n := start; n <= MaxLength; n++ {
+		p.Length = n
+		g, err := New(nil, p)
+		if err == ErrUnsatisfiable {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if g.Entropy() >= bits {
+			return n, nil
+		}
+		// A password of length l has at most l * log2(size) bits, so shorter
+		// lengths can be skipped.
+		var size int
+		for _, c := range g.classes {
+			size += len(c.chars)
+		}
+		if l := randchars.MinLength(size, bits); l > n+1 {
+			n = l - 1
+		}
+	}
+	return 0, fmt.Errorf("password: no length up to %d has %.1f bits of entropy", MaxLength, bits)
+}
+
+// symbolSet returns the deduplicated symbols in s, or DefaultSymbols if s is
+// empty. An error is returned if s contains letters, digits, or non-ASCII
+// characters.
+func symbolSet(s string) (string, error) {
+	if s == "" {
+		return DefaultSymbols, nil
+	}
+	cs, err := randchars.NewSymbolCharset(s)
+	if err != nil {
+		return "", fmt.Errorf("password: %w", err)
+	}
+	return cs.String(), nil
+}
+
+// count fills the ways table.
+func (g *Generator) count() {
+	g.ways = make([][]*big.Int, len(g.classes)+1)
+	g.ways[0] = make([]*big.Int, g.length+1)
+	for t := range g.ways[0] {
+		g.ways[0][t] = new(big.Int)
+	}
+	g.ways[0][0].SetInt64(1)
+	for j, c := range g.classes {
+		row := make([]*big.Int, g.length+1)
+		for t := range row {
+			row[t] = new(big.Int)
+			for k := c.min; k <= c.max && k <= t; k++ {
+				row[t].Add(row[t], g.term(j, c, t, k))
+			}
+		}
+		g.ways[j+1] = row
+	}
+}
+
+// term returns the number of strings of length t that use exactly k
+// characters from c, which is classes[j], and satisfy the constraints of
+// classes[:j] with the rest.
+func (g *Generator) term(j int, c class, t, k int) *big.Int {
+	v := new(big.Int).Binomial(int64(t), int64(k))
+	v.Mul(v, new(big.Int).Exp(big.NewInt(int64(len(c.chars))), big.NewInt(int64(k)), nil))
+	return v.Mul(v, g.ways[j][t-k])
+}
+
+// Generate returns a password that satisfies the Generator's Policy.
+func (g *Generator) Generate() []byte {
+	// Choose how many characters come from each class, weighted by the number
+	// of passwords with that composition, working back from the last class.
+	counts := make([]int, len(g.classes))
+	t := g.length
+	for j := len(g.classes) - 1; j >= 0; j-- {
+		c := g.classes[j]
+		v := g.bigN(g.ways[j+1][t])
+		for k := c.min; ; k++ {
+			w := g.term(j, c, t, k)
+			if v.Cmp(w) < 0 {
+				counts[j] = k
+				break
+			}
+			v.Sub(v, w)
+		}
+		t -= counts[j]
+	}
+	b := make([]byte, 0, g.length)
+	for j, c := range g.classes {
+		for i := 0; i < counts[j]; i++ {
+			b = append(b, c.chars[g.r.IntN(len(c.chars))])
+		}
+	}
+	randchars.Shuffle(g.r, b)
+	return b
+}
+
+// bigN returns an unbiased random value in [0, n).
+func (g *Generator) bigN(n *big.Int) *big.Int {
+	bits := n.BitLen()
+	buf := make([]byte, (bits+7)/8)
+	v := new(big.Int)
+	for {
+		for i := range buf {
+			buf[i] = byte(g.r.IntN(256))
+		}
+		// discard the bits above n's highest bit
+		if r := bits % 8; r != 0 {
+			buf[0] &= byte(1<<r - 1)
+		}
+		v.SetBytes(buf)
+		if v.Cmp(n) < 0 {
+			return v
+		}
+	}
+}
+
+// Count returns the number of passwords that satisfy the Generator's Policy.
+func (g *Generator) Count() *big.Int {
+	return new(big.Int).Set(g.ways[len(g.classes)][g.length])
+}
+
+// Entropy returns the entropy, in bits, of the generated passwords. Because
+// every password satisfying the Policy is equally likely, this is log2 of
+// Count.
+func (g *Generator) Entropy() float64 {
+	return log2(g.ways[len(g.classes)][g.length])
+}
+
+// log2 returns log2(n) for n > 0.
+func log2(n *big.Int) float64 {
+	shift := n.BitLen() - 53
+	if shift <= 0 {
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return math.Log2(f)
+	}
+	f, _ := new(big.Float).SetInt(new(big.Int).Rsh(n, uint(shift))).Float64()
+	return math.Log2(f) + float64(shift)
+}
@@ -0,0 +1,189 @@
+package password
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mohae/randchars"
+	"github.com/mohae/randchars/crandchars"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Policy
+		err  string
+	}{
+		{"zero length", Policy{}, "password: 0: length must be between 1 and 1024"},
+		{"too long", Policy{Length: MaxLength + 1}, "password: 1025: length must be between 1 and 1024"},
+		{"bad symbols", Policy{Length: 8, SymbolSet: "!a"}, "password: \"!a\": symbols may not contain letters or digits"},
+		{"negative min", Policy{Length: 8, Upper: Class{Min: -1}}, "password: upper: -1: minimum must be >= 0"},
+		{"min > max", Policy{Length: 8, Digits: Class{Min: 3, Max: 2}}, "password: digits: minimum of 3 is greater than maximum of 2"},
+		{"excluded min", Policy{Length: 8, Symbols: Class{Min: 1, Max: -1}}, "password: symbols: minimum of 1 for an excluded class"},
+		{"mins too long", Policy{Length: 4, Lower: Class{Min: 3}, Upper: Class{Min: 2}}, ErrUnsatisfiable.Error()},
+		{"maxes too short", Policy{Length: 4, Lower: Class{Max: 1}, Upper: Class{Max: 1}, Digits: Class{Max: 1}, Symbols: Class{Max: -1}}, ErrUnsatisfiable.Error()},
+		{"valid", Policy{Length: 20, Upper: Class{Min: 2}, Digits: Class{Min: 2}, Symbols: Class{Min: 1}}, ""},
+	}
+	for _, test := range tests {
+		_, err := New(randchars.NewGenerator(), test.p)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%s: got %q; want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%s: got no error; want %q", test.name, test.err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	p := Policy{
+		Length:    20,
+		Lower:     Class{Max: 10},
+		Upper:     Class{Min: 2},
+		Digits:    Class{Min: 2, Max: 4},
+		Symbols:   Class{Min: 1},
+		SymbolSet: "-_.",
+	}
+	for _, r := range []randchars.IntNer{randchars.NewGeneratorWithSeed(0), crandchars.New()} {
+		g, err := New(r, p)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		for i := 0; i < 1000; i++ {
+			b := g.Generate()
+			if len(b) != p.Length {
+				t.Fatalf("%q: got length %d; want %d", b, len(b), p.Length)
+			}
+			var l, u, d, s int
+			for _, c := range b {
+				switch {
+				case strings.IndexByte(lower, c) >= 0:
+					l++
+				case strings.IndexByte(upper, c) >= 0:
+					u++
+				case strings.IndexByte(digits, c) >= 0:
+					d++
+				case strings.IndexByte(p.SymbolSet, c) >= 0:
+					s++
+				default:
+					t.Fatalf("%q: unexpected character %q", b, c)
+				}
+			}
+			if l > 10 || u < 2 || d < 2 || d > 4 || s < 1 {
+				t.Fatalf("%q: does not satisfy the policy", b)
+			}
+		}
+	}
+}
+
+func TestGenerateUniform(t *testing.T) {
+	// a lowercase letter and a digit, in either order: 520 passwords.
+	p := Policy{
+		Length:  2,
+		Lower:   Class{Min: 1},
+		Upper:   Class{Max: -1},
+		Digits:  Class{Min: 1},
+		Symbols: Class{Max: -1},
+	}
+	g, err := New(randchars.NewGeneratorWithSeed(0), p)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if g.Count().Int64() != 520 {
+		t.Fatalf("got count %s; want 520", g.Count())
+	}
+	const per = 200
+	counts := make(map[string]int)
+	for i := 0; i < 520*per; i++ {
+		counts[string(g.Generate())]++
+	}
+	if len(counts) != 520 {
+		t.Fatalf("got %d distinct passwords; want 520", len(counts))
+	}
+	var chi float64
+	for _, n := range counts {
+		d := float64(n - per)
+		chi += d * d / per
+	}
+	// the critical value for 519 degrees of freedom at p = 0.001
+	if chi > 624.3 {
+		t.Errorf("chi-square %.2f exceeds 624.3", chi)
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	tests := []struct {
+		p        Policy
+		expected float64
+	}{
+		{Policy{Length: 16, Symbols: Class{Max: -1}}, 16 * math.Log2(62)},
+		{Policy{Length: 8, Lower: Class{Max: -1}, Upper: Class{Max: -1}, Symbols: Class{Max: -1}}, 8 * math.Log2(10)},
+		{Policy{Length: 2, Lower: Class{Min: 1}, Upper: Class{Max: -1}, Digits: Class{Min: 1}, Symbols: Class{Max: -1}}, math.Log2(520)},
+		{Policy{Length: 64}, 64 * math.Log2(75)},
+	}
+	for _, test := range tests {
+		g, err := New(randchars.NewGenerator(), test.p)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if math.Abs(g.Entropy()-test.expected) > 1e-9 {
+			t.Errorf("%+v: got %f; want %f", test.p, g.Entropy(), test.expected)
+		}
+	}
+	// a constraint removes passwords so lowers the entropy
+	g, _ := New(randchars.NewGenerator(), Policy{Length: 20, Upper: Class{Min: 2}, Digits: Class{Min: 2}, Symbols: Class{Min: 1}})
+	if e := g.Entropy(); e >= 20*math.Log2(75) || e < 20*math.Log2(75)-1 {
+		t.Errorf("got %f; want slightly less than %f", e, 20*math.Log2(75))
+	}
+}
+
+func TestMinLength(t *testing.T) {
+	tests := []struct {
+		p        Policy
+		bits     float64
+		expected int
+		err      string
+	}{
+		{Policy{Symbols: Class{Max: -1}}, 128, 22, ""},
+		{Policy{Lower: Class{Max: -1}, Upper: Class{Max: -1}, Symbols: Class{Max: -1}}, 20, 7, ""},
+		{Policy{Upper: Class{Min: 2}, Digits: Class{Min: 2}, Symbols: Class{Min: 1}}, 100, 17, ""},
+		{Policy{Lower: Class{Min: 6}, Digits: Class{Min: 2}}, 1, 8, ""},
+		{Policy{Lower: Class{Max: 4}, Upper: Class{Max: -1}, Digits: Class{Max: -1}, Symbols: Class{Max: -1}}, 100, 0, "password: no length up to 1024 has 100.0 bits of entropy"},
+		{Policy{Digits: Class{Min: -1}}, 100, 0, "password: digits: -1: minimum must be >= 0"},
+	}
+	for _, test := range tests {
+		n, err := MinLength(test.p, test.bits)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%+v: got %q; want %q", test.p, err, test.err)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%+v: got no error; want %q", test.p, test.err)
+			continue
+		}
+		if n != test.expected {
+			t.Errorf("%+v: got %d; want %d", test.p, n, test.expected)
+			continue
+		}
+		// the length is the first with at least the requested bits
+		for _, l := range []int{n - 1, n} {
+			test.p.Length = l
+			g, err := New(nil, test.p)
+			if err == ErrUnsatisfiable {
+				continue
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if (g.Entropy() >= test.bits) != (l == n) {
+				t.Errorf("%+v: length %d has %f bits", test.p, l, g.Entropy())
+			}
+		}
+	}
+}
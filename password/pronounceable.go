@@ -0,0 +1,132 @@
+package password
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mohae/randchars"
+)
+
+const (
+	// Consonants are the consonants used in pronounceable syllables. Letters
+	// whose sound depends on their neighbours, c, q, x, and y, or that are
+	// easily confused, w, aren't used.
+	Consonants = "bdfghjklmnprstvz"
+	// Vowels are the vowels used in pronounceable syllables.
+	Vowels = "aeiou"
+)
+
+// PronounceablePolicy defines the pronounceable passwords to generate: a
+// number of consonant-vowel syllables, e.g. "ba" or "to", with digits and
+// symbols placed among the syllables, possibly first or last.
+type PronounceablePolicy struct {
+	// Syllables is the number of syllables; each is two characters.
+	Syllables int
+	// Digits is the number of digits.
+	Digits int
+	// Symbols is the number of symbols.
+	Symbols int
+	// SymbolSet is the set of allowed symbols; if empty, DefaultSymbols is
+	// used. It may not contain letters or digits.
+	SymbolSet string
+}
+
+// token kinds
+const (
+	syllable = iota
+	digit
+	symbol
+)
+
+// PronounceableGenerator generates pronounceable passwords. A
+// PronounceableGenerator is not safe for concurrent use unless its
+// randchars.IntNer is.
+type PronounceableGenerator struct {
+	r       randchars.IntNer
+	symbols string
+	// tokens are the kinds of the password's tokens; they are shuffled to
+	// place the digits and symbols.
+	tokens []int
+	length int
+	count  *big.Int
+}
+
+// NewPronounceable returns a PronounceableGenerator that uses r to generate
+// passwords satisfying p. An error is returned if p is invalid.
+func NewPronounceable(r randchars.IntNer, p PronounceablePolicy) (*PronounceableGenerator, error) {
+	if p.Syllables <= 0 {
+		return nil, fmt.Errorf("password: %d: syllables must be > 0", p.Syllables)
+	}
+	if p.Digits < 0 {
+		return nil, fmt.Errorf("password: %d: digits must be >= 0", p.Digits)
+	}
+	if p.Symbols < 0 {
+		return nil, fmt.Errorf("password: %d: symbols must be >= 0", p.Symbols)
+	}
+	length := 2*p.Syllables + p.Digits + p.Symbols
+	if length > MaxLength {
+		return nil, fmt.Errorf("password: %d: length must be between 1 and %d", length, MaxLength)
+	}
+	symbols, err := symbolSet(p.SymbolSet)
+	if err != nil {
+		return nil, err
+	}
+	g := &PronounceableGenerator{r: r, symbols: symbols, length: length}
+	for _, t := range []struct{ kind, n int }{{syllable, p.Syllables}, {digit, p.Digits}, {symbol, p.Symbols}} {
+		for i := 0; i < t.n; i++ {
+			g.tokens = append(g.tokens, t.kind)
+		}
+	}
+	// Every syllable is two letters and the digits and symbols aren't
+	// letters, so each arrangement of the tokens, and each choice of their
+	// characters, results in a different password: the count is the number of
+	// arrangements, (s+d+y)!/(s!d!y!), times the choices for each token.
+	n := len(g.tokens)
+	g.count = new(big.Int).Binomial(int64(n), int64(p.Syllables))
+	g.count.Mul(g.count, new(big.Int).Binomial(int64(n-p.Syllables), int64(p.Digits)))
+	for _, t := range []struct{ choices, n int }{
+		{len(Consonants) * len(Vowels), p.Syllables},
+		{len(digits), p.Digits},
+		{len(symbols), p.Symbols},
+	} {
+		g.count.Mul(g.count, new(big.Int).Exp(big.NewInt(int64(t.choices)), big.NewInt(int64(t.n)), nil))
+	}
+	return g, nil
+}
+
+// Generate returns a pronounceable password. Each password the
+// PronounceableGenerator can generate is equally likely.
+func (g *PronounceableGenerator) Generate() []byte {
+	// a uniformly random permutation of the tokens results in each
+	// arrangement of their kinds being equally likely
+	randchars.Shuffle(g.r, g.tokens)
+	b := make([]byte, 0, g.length)
+	for _, t := range g.tokens {
+		switch t {
+		case syllable:
+			b = append(b, Consonants[g.r.IntN(len(Consonants))], Vowels[g.r.IntN(len(Vowels))])
+		case digit:
+			b = append(b, digits[g.r.IntN(len(digits))])
+		case symbol:
+			b = append(b, g.symbols[g.r.IntN(len(g.symbols))])
+		}
+	}
+	return b
+}
+
+// Len returns the length of the generated passwords.
+func (g *PronounceableGenerator) Len() int {
+	return g.length
+}
+
+// Count returns the number of passwords the PronounceableGenerator can
+// generate.
+func (g *PronounceableGenerator) Count() *big.Int {
+	return new(big.Int).Set(g.count)
+}
+
+// Entropy returns the entropy, in bits, of the generated passwords. Because
+// every password is equally likely, this is log2 of Count.
+func (g *PronounceableGenerator) Entropy() float64 {
+	return log2(g.count)
+}
@@ -0,0 +1,146 @@
+package password
+
+import (
+	"math"
+	"regexp"
+	"testing"
+
+	"github.com/mohae/randchars"
+	"github.com/mohae/randchars/crandchars"
+)
+
+func TestNewPronounceable(t *testing.T) {
+	tests := []struct {
+		name string
+		p    PronounceablePolicy
+		err  string
+	}{
+		{"no syllables", PronounceablePolicy{}, "password: 0: syllables must be > 0"},
+		{"negative digits", PronounceablePolicy{Syllables: 4, Digits: -1}, "password: -1: digits must be >= 0"},
+		{"negative symbols", PronounceablePolicy{Syllables: 4, Symbols: -1}, "password: -1: symbols must be >= 0"},
+		{"too long", PronounceablePolicy{Syllables: 512, Digits: 1}, "password: 1025: length must be between 1 and 1024"},
+		{"bad symbols", PronounceablePolicy{Syllables: 4, Symbols: 1, SymbolSet: "!1"}, "password: \"!1\": symbols may not contain letters or digits"},
+		{"valid", PronounceablePolicy{Syllables: 4, Digits: 2, Symbols: 1}, ""},
+	}
+	for _, test := range tests {
+		_, err := NewPronounceable(randchars.NewGenerator(), test.p)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%s: got %q; want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%s: got no error; want %q", test.name, test.err)
+		}
+	}
+}
+
+func TestPronounceableGenerate(t *testing.T) {
+	p := PronounceablePolicy{Syllables: 5, Digits: 2, Symbols: 1, SymbolSet: "-_"}
+	re := regexp.MustCompile(`^(?:[` + Consonants + `][` + Vowels + `]|[0-9]|[-_])+$`)
+	for _, r := range []randchars.IntNer{randchars.NewGeneratorWithSeed(0), crandchars.New()} {
+		g, err := NewPronounceable(r, p)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		for i := 0; i < 1000; i++ {
+			b := g.Generate()
+			if len(b) != g.Len() || len(b) != 13 {
+				t.Fatalf("%q: got length %d; want 13", b, len(b))
+			}
+			if !re.Match(b) {
+				t.Fatalf("%q: is not made up of syllables, digits, and symbols", b)
+			}
+			var d, s int
+			for _, c := range b {
+				switch {
+				case c >= '0' && c <= '9':
+					d++
+				case c == '-' || c == '_':
+					s++
+				}
+			}
+			if d != 2 || s != 1 {
+				t.Fatalf("%q: got %d digits and %d symbols; want 2 and 1", b, d, s)
+			}
+		}
+	}
+}
+
+func TestPronounceablePlacement(t *testing.T) {
+	// digits and symbols aren't only placed between syllables.
+	g, err := NewPronounceable(randchars.NewGeneratorWithSeed(0), PronounceablePolicy{Syllables: 2, Digits: 1, Symbols: 1, SymbolSet: "-"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		b := g.Generate()
+		for _, pos := range []struct {
+			name string
+			c    byte
+		}{{"first", b[0]}, {"last", b[len(b)-1]}} {
+			switch {
+			case pos.c >= '0' && pos.c <= '9':
+				seen["digit "+pos.name] = true
+			case pos.c == '-':
+				seen["symbol "+pos.name] = true
+			}
+		}
+	}
+	for _, k := range []string{"digit first", "digit last", "symbol first", "symbol last"} {
+		if !seen[k] {
+			t.Errorf("got no password with a %s; want some", k)
+		}
+	}
+}
+
+func TestPronounceableUniform(t *testing.T) {
+	// a syllable and a digit, in either order: 2 * 80 * 10 passwords.
+	g, err := NewPronounceable(randchars.NewGeneratorWithSeed(0), PronounceablePolicy{Syllables: 1, Digits: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if g.Count().Int64() != 1600 {
+		t.Fatalf("got count %s; want 1600", g.Count())
+	}
+	const per = 50
+	counts := make(map[string]int)
+	for i := 0; i < 1600*per; i++ {
+		counts[string(g.Generate())]++
+	}
+	if len(counts) != 1600 {
+		t.Fatalf("got %d distinct passwords; want 1600", len(counts))
+	}
+	var chi float64
+	for _, n := range counts {
+		d := float64(n - per)
+		chi += d * d / per
+	}
+	// the critical value for 1599 degrees of freedom at p = 0.001
+	if chi > 1779.5 {
+		t.Errorf("chi-square %.2f exceeds 1779.5", chi)
+	}
+}
+
+func TestPronounceableEntropy(t *testing.T) {
+	tests := []struct {
+		p        PronounceablePolicy
+		expected float64
+	}{
+		{PronounceablePolicy{Syllables: 8}, 8 * math.Log2(80)},
+		{PronounceablePolicy{Syllables: 1, Digits: 1}, math.Log2(1600)},
+		// 6!/(4!1!1!) arrangements
+		{PronounceablePolicy{Syllables: 4, Digits: 1, Symbols: 1}, math.Log2(30) + 4*math.Log2(80) + math.Log2(10) + math.Log2(13)},
+	}
+	for _, test := range tests {
+		g, err := NewPronounceable(randchars.NewGenerator(), test.p)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if math.Abs(g.Entropy()-test.expected) > 1e-9 {
+			t.Errorf("%+v: got %f; want %f", test.p, g.Entropy(), test.expected)
+		}
+	}
+}
@@ -2,24 +2,37 @@
 // using a PRNG. Two different generators are provide: Generator and Base64.
 //
 // Generator provides more flexibility in the set of characters used:
-// a-zA-Z0-9, a-z0-9, A-Z0-9, a-zA-Z, a-z, A-Z, Base64, as defined in Table 1
-// of RFC 4648, and Base64URL, as defined in Table 2 of RFC 4648.
+// a-zA-Z0-9, a-z0-9, A-Z0-9, a-zA-Z, a-z, A-Z, 0-9, hexadecimal, printable
+// ASCII, Z85, Ascii85, Base64, as defined in Table 1 of RFC 4648, Base64URL,
+// as defined in Table 2 of RFC 4648, Base32 and Base32hex, as defined in
+// Tables 3 and 4 of RFC 4648, Crockford's Base32, with an optional check
+// symbol, and Base58, using the Bitcoin or Flickr alphabets, with an optional
+// Base58Check checksum. Any other set of ASCII characters can be used by
+// creating a Charset and passing it to Chars. Non-ASCII alphabets, including
+// those with more than 256 symbols, are supported by RuneCharset and Runes.
 //
 // Base64 generates a chunk of Base 64 random characters. The character set
 // used is from Table 1 of RFC 4648.
 //
-// Calls to the package functions using the package global genarator are
-// threadsafe.
+// The generators use a Source for their random values: PCG, XORoShiRo128+,
+// xoshiro256**, wyrand, and ChaCha8 are provided.
+//
+// Calls to the package functions using the package global generators are
+// threadsafe. Each call uses one of a pool of independently seeded generators,
+// so concurrent calls don't contend with each other unless the package has
+// been seeded with Seed, which makes its output reproducible.
 package randchars
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"slices"
+	"strings"
 	"sync"
-
-	pcg "github.com/dgryski/go-pcgr"
-	xoro "github.com/dgryski/go-xoroshiro"
+	"sync/atomic"
 )
 
 const (
@@ -31,23 +44,113 @@ const (
 	upperAlpha    = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	base64        = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789+/"
 	base64URL     = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"
+	digits        = "0123456789"
+	base32        = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+	base32Hex     = "0123456789ABCDEFGHIJKLMNOPQRSTUV"
+	crockford     = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base58        = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	base58Flickr  = "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+	lowerHex      = "0123456789abcdef"
+	upperHex      = "0123456789ABCDEF"
+	z85           = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+	ascii85       = "!\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstu"
+	printable     = "!\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+	// shellSafeSymbols are the symbols that are never special to sh, bash,
+	// or zsh, wherever they are in an unquoted word; = is excluded because
+	// zsh expands a word starting with it.
+	shellSafeSymbols = "%+,-./:@_"
+	// the context-safe charsets; see charset.go for what they exclude
+	shellSafe   = alphaNum + shellSafeSymbols
+	urlPath     = alphaNum + "-._~!$&'()*+,;=:@"
+	jsonSafe    = alphaNum + "!#$%'()*+,-./:;=?@[]^_`{|}~"
+	xmlAttr     = alphaNum + "!#$%()*+,-./:;=?@[\\]^_`{|}~"
+	dsnPassword = alphaNum + "-._~"
+)
+
+// Errors returned by the error returning variants of the generation funcs.
+var (
+	// ErrNegativeLength is returned when a negative number of characters is
+	// requested.
+	ErrNegativeLength = errors.New("randchars: negative length")
+	// ErrEntropy is returned when a value could not be read from the CSPRNG.
+	ErrEntropy = errors.New("randchars: entropy read error")
+)
+
+// The package-level generators.
+var (
+	gen          = newShared(NewGeneratorWithSeed)
+	genBase64    = newShared(NewBase64GeneratorWithSeed)
+	genBase64URL = newShared(NewBase64URLGeneratorWithSeed)
 )
 
-// use own
-var gen *Generator
-var mu sync.Mutex
+// reader is the CSPRNG used for seeding; it is only changed by tests.
+var reader = rand.Reader
 
-// for base64 stuff
-var genBase64 *Base64Generator
-var mu64 sync.Mutex
+// shared is a package-level generator that can be used concurrently without
+// contention: each call gets a generator from a pool of independently seeded
+// generators. Once it has been seeded, so that its output is reproducible,
+// the calls use a single seeded generator, guarded by a mutex, until it is
+// reseeded.
+type shared[T any] struct {
+	pool   sync.Pool
+	mu     sync.Mutex
+	seeded atomic.Pointer[T] // only changed while mu is held
+}
 
-// for base64url stuff
-var genBase64URL *Base64URLGenerator
-var mu64URL sync.Mutex
+// newShared returns a shared generator whose pool's generators are created
+// by newGen, seeded from the CSPRNG. If the CSPRNG fails, the pool returns
+// the error instead of a generator.
+func newShared[T any](newGen func(seed int64) *T) *shared[T] {
+	return &shared[T]{pool: sync.Pool{New: func() any {
+		seed, err := ReadInt64()
+		if err != nil {
+			return err
+		}
+		return newGen(seed)
+	}}}
+}
 
-func init() {
-	gen = NewGenerator()
-	genBase64 = NewBase64Generator()
+// get returns a generator for the caller's exclusive use; it must be
+// released with put. This will panic if a new generator can't be seeded.
+func (s *shared[T]) get() *T {
+	g, err := s.tryGet()
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// tryGet is get but returns an error, wrapping ErrEntropy, if a new
+// generator can't be seeded.
+func (s *shared[T]) tryGet() (*T, error) {
+	if s.seeded.Load() != nil {
+		s.mu.Lock()
+		if g := s.seeded.Load(); g != nil {
+			return g, nil
+		}
+		s.mu.Unlock()
+	}
+	g := s.pool.Get()
+	if err, ok := g.(error); ok {
+		return nil, err
+	}
+	return g.(*T), nil
+}
+
+// put releases a generator returned by get.
+func (s *shared[T]) put(g *T) {
+	if g == s.seeded.Load() {
+		s.mu.Unlock()
+		return
+	}
+	s.pool.Put(g)
+}
+
+// seed makes the calls use g, or the pool if g is nil.
+func (s *shared[T]) seed(g *T) {
+	s.mu.Lock()
+	s.seeded.Store(g)
+	s.mu.Unlock()
 }
 
 // Generatorer is an interface for generators.
@@ -62,262 +165,1048 @@ type Generatorer interface {
 	Base64URL(n int) []byte
 }
 
-// Generator generates the random ASCII characters.  It relies on a PRNG that
-// implements PCG: www.pcg-random.org.
+// CharsGenerator is implemented by generators that generate characters from
+// any Charset. Both Generator and crandchars.Generator implement it.
+type CharsGenerator interface {
+	CharsFiller
+	Chars(cs Charset, n int) []byte
+	AppendChars(dst []byte, cs Charset, n int) []byte
+	Generate(cs Charset, n int) ([]byte, error)
+}
+
+// Generator generates the random ASCII characters using a Source. Unless
+// another Source is specified, it relies on a PRNG that implements PCG:
+// www.pcg-random.org.
 type Generator struct {
-	rng pcg.Rand
+	src Source
 }
 
 // Returns a seeded Generator that's ready to use.
 func NewGenerator() *Generator {
-	return &Generator{pcg.New(Int64(), 0)}
+	return &Generator{NewPCGSource(Int64(), 0)}
 }
 
 // NewGeneratorWithSeed a Generator using the received value as its seed.
 func NewGeneratorWithSeed(seed int64) *Generator {
-	return &Generator{pcg.New(seed, 0)}
+	return &Generator{NewPCGSource(seed, 0)}
 }
 
 // NewGeneratorSeedWithState a Generator using the received values as its seed
 // and state.
 func NewGeneratorSeedWithState(seed, state int64) *Generator {
-	return &Generator{pcg.New(seed, state)}
+	return &Generator{NewPCGSource(seed, state)}
+}
+
+// NewGeneratorWithSource returns a Generator that uses src, e.g. a
+// Xoshiro256Source or a ChaCha8Source, instead of PCG.
+func NewGeneratorWithSource(src Source) *Generator {
+	return &Generator{src}
 }
 
 // Seed seeds the Generator's prng.
 func (g *Generator) Seed(n int64) {
-	g.rng.Seed(n)
+	g.src.Seed(n)
 }
 
-// SeedWithState seeds the Generator's prng and set's its state.
+// SeedWithState seeds the Generator's prng and set's its state. Only a
+// PCGSource has a state; for other Sources, the state is ignored.
 func (g *Generator) SeedWithState(seed, state int64) {
-	g.rng.SeedWithState(seed, state)
+	if s, ok := g.src.(interface{ SeedWithState(seed, state int64) }); ok {
+		s.SeedWithState(seed, state)
+		return
+	}
+	g.src.Seed(seed)
 }
 
 // ReSeed seeds the Generator's prng using a value obtained from a CSPRNG.
 func (g *Generator) ReSeed() {
-	g.rng.Seed(Int64())
+	g.src.Seed(Int64())
 }
 
-// AlphaNum returns a randomly generated []byte of length n using a-zA-Z0-9.
-// This will panic if n < 0.
-func (g *Generator) AlphaNum(n int) []byte {
+// Chars returns a randomly generated []byte of length n using the characters
+// in cs. This will panic if n < 0 or cs is empty.
+func (g *Generator) Chars(cs Charset, n int) []byte {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	id := make([]byte, 0, n)
-	for i := 0; i < n; i++ {
-		id = append(id, alphaNum[g.rng.Bound(uint32(len(alphaNum)))])
+	if cs.Len() == 0 {
+		panic(ErrEmptyCharset)
 	}
-	return id
+	b := make([]byte, n)
+	g.FillChars(b, cs)
+	return b
 }
 
-// Alpha returns a randomly generated []byte of length n using a-zA-Z. This
-// will panic if n < 0.
-func (g *Generator) Alpha(n int) []byte {
+// Generate returns a randomly generated []byte of length n using the
+// characters in cs. Unlike Chars, an error is returned instead of panicking:
+// ErrNegativeLength if n < 0 and ErrEmptyCharset if cs is empty.
+func (g *Generator) Generate(cs Charset, n int) ([]byte, error) {
 	if n < 0 {
-		panic(fmt.Sprintf("%d: value out of bounds", n))
+		return nil, ErrNegativeLength
 	}
-	id := make([]byte, 0, n)
-	for i := 0; i < n; i++ {
-		id = append(id, alpha[g.rng.Bound(uint32(len(alpha)))])
+	if cs.Len() == 0 {
+		return nil, ErrEmptyCharset
 	}
-	return id
+	return g.Chars(cs, n), nil
 }
 
-// LowerAlphaNum returns a randomly generated []byte of length n using a-z0-9.
-// This will panic if n < 0.
-func (g *Generator) LowerAlphaNum(n int) []byte {
+// AppendChars appends n randomly generated characters using the characters in
+// cs to dst and returns the extended slice. If dst has sufficient capacity, no
+// allocation is done. This will panic if n < 0 or cs is empty.
+func (g *Generator) AppendChars(dst []byte, cs Charset, n int) []byte {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	id := make([]byte, 0, n)
-	for i := 0; i < n; i++ {
-		id = append(id, lowerAlphaNum[g.rng.Bound(uint32(len(lowerAlphaNum)))])
+	l := len(dst)
+	dst = slices.Grow(dst, n)[:l+n]
+	g.FillChars(dst[l:], cs)
+	return dst
+}
+
+// FillChars fills dst with randomly generated characters using the characters
+// in cs. This will panic if cs is empty.
+//
+// Each 32 bit draw from the Source is used for multiple characters. When the
+// length of cs is a power of 2, the draw is sliced into groups of bits;
+// otherwise, the characters are extracted from the draw using batched
+// multiply-shift with rejection, which keeps the output unbiased.
+func (g *Generator) FillChars(dst []byte, cs Charset) {
+	if cs.Len() == 0 {
+		panic(ErrEmptyCharset)
+	}
+	if cs.batch == 0 {
+		g.fillBits(dst, cs)
+		return
+	}
+	// calling the default PCGSource directly, instead of through the Source
+	// interface, saves an interface call per draw
+	chars := cs.chars
+	if s, ok := g.src.(*PCGSource); ok {
+		for len(dst) > 0 {
+			r := s.Uint32()
+			if r*cs.product < cs.batchThreshold {
+				continue
+			}
+			n := min(cs.batch, len(dst))
+			putBatch(dst[:n], chars, r)
+			dst = dst[n:]
+		}
+		return
+	}
+	for len(dst) > 0 {
+		r := g.src.Uint32()
+		if r*cs.product < cs.batchThreshold {
+			continue
+		}
+		n := min(cs.batch, len(dst))
+		putBatch(dst[:n], chars, r)
+		dst = dst[n:]
 	}
-	return id
 }
 
-// LowerAlpha returns a randomly generated []byte of length n using a-z. This
-// will panic if n < 0.
-func (g *Generator) LowerAlpha(n int) []byte {
-	if n < 0 {
+// putBatch fills dst with the characters extracted from the draw r using
+// multiply-shift.
+func putBatch(dst []byte, chars string, r uint32) {
+	for i := range dst {
+		m := uint64(r) * uint64(len(chars))
+		dst[i] = chars[m>>32]
+		r = uint32(m)
+	}
+}
+
+// fillBits fills dst using a Charset whose length is a power of 2.
+func (g *Generator) fillBits(dst []byte, cs Charset) {
+	if cs.bits == 0 {
+		for i := range dst {
+			dst[i] = cs.chars[0]
+		}
+		return
+	}
+	chars := cs.chars
+	shift := cs.bits
+	per := int(32 / shift)
+	if s, ok := g.src.(*PCGSource); ok {
+		for len(dst) > 0 {
+			n := min(per, len(dst))
+			putBits(dst[:n], chars, shift, s.Uint32())
+			dst = dst[n:]
+		}
+		return
+	}
+	for len(dst) > 0 {
+		n := min(per, len(dst))
+		putBits(dst[:n], chars, shift, g.src.Uint32())
+		dst = dst[n:]
+	}
+}
+
+// putBits fills dst with the characters selected by successive groups of
+// shift bits of the draw r; the length of chars is 1<<shift.
+func putBits(dst []byte, chars string, shift uint, r uint32) {
+	mask := uint32(len(chars) - 1)
+	for i := range dst {
+		dst[i] = chars[r&mask]
+		r >>= shift
+	}
+}
+
+// IntN returns an unbiased random int in [0, n). This will panic if n <= 0.
+func (g *Generator) IntN(n int) int {
+	if n <= 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	id := make([]byte, 0, n)
-	for i := 0; i < n; i++ {
-		id = append(id, lowerAlpha[g.rng.Bound(uint32(len(lowerAlpha)))])
+	if uint64(n) <= math.MaxUint32 {
+		return int(g.src.Uint32N(uint32(n)))
 	}
-	return id
+	return int(g.src.Uint64N(uint64(n)))
 }
 
-// UpperAlphaNum returns a randomly generated []byte of length n using A-Z0-9.
-// This will panic if n < 0.
-func (g *Generator) UpperAlphaNum(n int) []byte {
+// Runes returns n randomly generated runes using the runes in cs. This will
+// panic if n < 0 or cs is empty.
+func (g *Generator) Runes(cs RuneCharset, n int) []rune {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	id := make([]byte, 0, n)
+	if cs.Len() == 0 {
+		panic(ErrEmptyCharset)
+	}
+	r := make([]rune, n)
+	bound := uint32(len(cs.runes))
 	for i := 0; i < n; i++ {
-		id = append(id, upperAlphaNum[g.rng.Bound(uint32(len(upperAlphaNum)))])
+		for {
+			v := g.src.Uint32()
+			if v >= cs.threshold32 {
+				r[i] = cs.runes[v%bound]
+				break
+			}
+		}
 	}
-	return id
+	return r
 }
 
-// UpperAlpha returns a randomly generated []byte of length n using A-Z. This
-// will panic if n < 0.
-func (g *Generator) UpperAlpha(n int) []byte {
+// GenerateRunes returns n randomly generated runes using the runes in cs.
+// Unlike Runes, an error is returned instead of panicking: ErrNegativeLength
+// if n < 0 and ErrEmptyCharset if cs is empty.
+func (g *Generator) GenerateRunes(cs RuneCharset, n int) ([]rune, error) {
 	if n < 0 {
-		panic(fmt.Sprintf("%d: value out of bounds", n))
+		return nil, ErrNegativeLength
 	}
-	id := make([]byte, 0, n)
-	for i := 0; i < n; i++ {
-		id = append(id, upperAlpha[g.rng.Bound(uint32(len(upperAlpha)))])
+	if cs.Len() == 0 {
+		return nil, ErrEmptyCharset
 	}
-	return id
+	return g.Runes(cs, n), nil
+}
+
+// RuneString returns a string of n randomly generated runes using the runes
+// in cs. The length of the string, in bytes, depends on the runes chosen. This
+// will panic if n < 0 or cs is empty.
+func (g *Generator) RuneString(cs RuneCharset, n int) string {
+	return string(g.Runes(cs, n))
+}
+
+// AlphaNum returns a randomly generated []byte of length n using a-zA-Z0-9.
+// This will panic if n < 0.
+func (g *Generator) AlphaNum(n int) []byte {
+	return g.Chars(AlphaNumCharset, n)
+}
+
+// Alpha returns a randomly generated []byte of length n using a-zA-Z. This
+// will panic if n < 0.
+func (g *Generator) Alpha(n int) []byte {
+	return g.Chars(AlphaCharset, n)
+}
+
+// LowerAlphaNum returns a randomly generated []byte of length n using a-z0-9.
+// This will panic if n < 0.
+func (g *Generator) LowerAlphaNum(n int) []byte {
+	return g.Chars(LowerAlphaNumCharset, n)
+}
+
+// LowerAlpha returns a randomly generated []byte of length n using a-z. This
+// will panic if n < 0.
+func (g *Generator) LowerAlpha(n int) []byte {
+	return g.Chars(LowerAlphaCharset, n)
+}
+
+// UpperAlphaNum returns a randomly generated []byte of length n using A-Z0-9.
+// This will panic if n < 0.
+func (g *Generator) UpperAlphaNum(n int) []byte {
+	return g.Chars(UpperAlphaNumCharset, n)
+}
+
+// UpperAlpha returns a randomly generated []byte of length n using A-Z. This
+// will panic if n < 0.
+func (g *Generator) UpperAlpha(n int) []byte {
+	return g.Chars(UpperAlphaCharset, n)
 }
 
 // Base64 returns a randomly generated []byte of length n using Base64, as
 // defined in Table 1 of RFC 4648. This will panic if n < 0.
 func (g *Generator) Base64(n int) []byte {
-	if n < 0 {
-		panic(fmt.Sprintf("%d: value out of bounds", n))
-	}
-	b := make([]byte, n)
-	for i := 0; i < n; i++ {
-		b[i] = base64[g.rng.Bound(uint32(len(base64)))]
-	}
-	return b
+	return g.Chars(Base64Charset, n)
 }
 
 // Base64URL returns a randomly generated []byte of length n using Base64URL,
 // as defined in Table 2 of RFC 4648. This will panic if n < 0.
 func (g *Generator) Base64URL(n int) []byte {
+	return g.Chars(Base64URLCharset, n)
+}
+
+// Digits returns a randomly generated []byte of length n using 0-9. This will
+// panic if n < 0.
+func (g *Generator) Digits(n int) []byte {
+	return g.Chars(DigitsCharset, n)
+}
+
+// LowerHex returns a randomly generated []byte of length n using 0-9a-f. This
+// will panic if n < 0.
+func (g *Generator) LowerHex(n int) []byte {
+	return g.Chars(LowerHexCharset, n)
+}
+
+// UpperHex returns a randomly generated []byte of length n using 0-9A-F. This
+// will panic if n < 0.
+func (g *Generator) UpperHex(n int) []byte {
+	return g.Chars(UpperHexCharset, n)
+}
+
+// CharsNoLeadingZero returns a randomly generated []byte of length n using
+// the characters in cs whose first character isn't '0', so a number, e.g.
+// from Digits or LowerHex, is n digits wide. The first character is chosen
+// without bias from the other characters in cs. This will panic if n < 0 or
+// cs has no characters other than '0'.
+func (g *Generator) CharsNoLeadingZero(cs Charset, n int) []byte {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
 	b := make([]byte, n)
-	for i := 0; i < n; i++ {
-		b[i] = base64URL[g.rng.Bound(uint32(len(base64URL)))]
+	if n == 0 {
+		return b
 	}
+	b[0] = NonZero(g, cs)
+	g.FillChars(b[1:], cs)
 	return b
 }
 
-// Seed seeds the Generator's prng.
+// NonZero returns a character, chosen without bias, from the characters in
+// cs other than '0' using r. This will panic if cs has no characters other
+// than '0'.
+func NonZero(r IntNer, cs Charset) byte {
+	chars := cs.String()
+	z := strings.IndexByte(chars, '0')
+	if z < 0 {
+		if len(chars) == 0 {
+			panic(ErrEmptyCharset)
+		}
+		return chars[r.IntN(len(chars))]
+	}
+	if len(chars) == 1 {
+		panic(ErrEmptyCharset)
+	}
+	i := r.IntN(len(chars) - 1)
+	if i >= z {
+		i++
+	}
+	return chars[i]
+}
+
+// Z85 returns a randomly generated []byte of length n using Z85, ZeroMQ's
+// Base85 alphabet. This will panic if n < 0.
+func (g *Generator) Z85(n int) []byte {
+	return g.Chars(Z85Charset, n)
+}
+
+// Ascii85 returns a randomly generated []byte of length n using the Ascii85
+// alphabet, ! to u. This will panic if n < 0.
+func (g *Generator) Ascii85(n int) []byte {
+	return g.Chars(Ascii85Charset, n)
+}
+
+// Printable returns a randomly generated []byte of length n using the
+// printable ASCII characters, ! to ~; space is excluded. This will panic if
+// n < 0.
+func (g *Generator) Printable(n int) []byte {
+	return g.Chars(PrintableCharset, n)
+}
+
+// ShellSafe returns a randomly generated []byte of length n using the
+// characters that are safe in an unquoted shell word; see ShellSafeCharset.
+// This will panic if n < 0.
+func (g *Generator) ShellSafe(n int) []byte {
+	return g.Chars(ShellSafeCharset, n)
+}
+
+// URLPath returns a randomly generated []byte of length n using the characters
+// that are safe in a URL path segment; see URLPathCharset. This will panic if
+// n < 0.
+func (g *Generator) URLPath(n int) []byte {
+	return g.Chars(URLPathCharset, n)
+}
+
+// JSONSafe returns a randomly generated []byte of length n using the characters
+// that are safe in a JSON string; see JSONSafeCharset. This will panic if
+// n < 0.
+func (g *Generator) JSONSafe(n int) []byte {
+	return g.Chars(JSONSafeCharset, n)
+}
+
+// XMLAttr returns a randomly generated []byte of length n using the characters
+// that are safe in an XML attribute value; see XMLAttrCharset. This will panic
+// if n < 0.
+func (g *Generator) XMLAttr(n int) []byte {
+	return g.Chars(XMLAttrCharset, n)
+}
+
+// DSNPassword returns a randomly generated []byte of length n using the
+// characters that are safe in a DSN password or a SQL string literal; see
+// DSNPasswordCharset. This will panic if n < 0.
+func (g *Generator) DSNPassword(n int) []byte {
+	return g.Chars(DSNPasswordCharset, n)
+}
+
+// Base32 returns a randomly generated []byte of length n using Base32, as
+// defined in Table 3 of RFC 4648. This will panic if n < 0.
+func (g *Generator) Base32(n int) []byte {
+	return g.Chars(Base32Charset, n)
+}
+
+// Base32Hex returns a randomly generated []byte of length n using Base32hex,
+// as defined in Table 4 of RFC 4648. This will panic if n < 0.
+func (g *Generator) Base32Hex(n int) []byte {
+	return g.Chars(Base32HexCharset, n)
+}
+
+// Crockford returns a randomly generated []byte of length n using Crockford's
+// Base32, https://www.crockford.com/base32.html. This will panic if n < 0.
+func (g *Generator) Crockford(n int) []byte {
+	return g.Chars(CrockfordCharset, n)
+}
+
+// Base58 returns a randomly generated []byte of length n using Bitcoin's
+// Base58 alphabet. This will panic if n < 0.
+func (g *Generator) Base58(n int) []byte {
+	return g.Chars(Base58Charset, n)
+}
+
+// Base58Flickr returns a randomly generated []byte of length n using
+// Flickr's Base58 alphabet. This will panic if n < 0.
+func (g *Generator) Base58Flickr(n int) []byte {
+	return g.Chars(Base58FlickrCharset, n)
+}
+
+// Seed seeds the package's prng, making the output of the package funcs
+// reproducible. Until ReSeed is called, the package funcs share a single
+// Generator, so concurrent calls contend for it.
 func Seed(n int64) {
-	mu.Lock()
-	gen.Seed(n)
-	mu.Unlock()
+	gen.seed(NewGeneratorWithSeed(n))
 }
 
-// SeedWithState seeds the Generator's prng and set's its state.
+// SeedWithState seeds the package's prng and set's its state; see Seed.
 func SeedWithState(seed, state int64) {
-	mu.Lock()
-	gen.SeedWithState(seed, state)
-	mu.Unlock()
+	gen.seed(NewGeneratorSeedWithState(seed, state))
 }
 
-// ReSeed seeds the Generator's prng using a value obtained from a CSPRNG.
+// ReSeed seeds the package's prng using values obtained from a CSPRNG. This
+// undoes Seed: concurrent calls to the package funcs use independently seeded
+// Generators and don't contend with each other.
 func ReSeed() {
-	mu.Lock()
-	gen.ReSeed()
-	mu.Unlock()
+	gen.seed(nil)
+}
+
+// AppendAlphaNum appends n randomly generated characters using a-zA-Z0-9 to
+// dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendAlphaNum(dst []byte, n int) []byte {
+	return g.AppendChars(dst, AlphaNumCharset, n)
+}
+
+// FillAlphaNum fills dst with randomly generated characters using a-zA-Z0-9.
+func (g *Generator) FillAlphaNum(dst []byte) {
+	g.FillChars(dst, AlphaNumCharset)
+}
+
+// AppendAlpha appends n randomly generated characters using a-zA-Z to dst and
+// returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendAlpha(dst []byte, n int) []byte {
+	return g.AppendChars(dst, AlphaCharset, n)
+}
+
+// FillAlpha fills dst with randomly generated characters using a-zA-Z.
+func (g *Generator) FillAlpha(dst []byte) {
+	g.FillChars(dst, AlphaCharset)
+}
+
+// AppendLowerAlphaNum appends n randomly generated characters using a-z0-9 to
+// dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendLowerAlphaNum(dst []byte, n int) []byte {
+	return g.AppendChars(dst, LowerAlphaNumCharset, n)
+}
+
+// FillLowerAlphaNum fills dst with randomly generated characters using a-z0-9.
+func (g *Generator) FillLowerAlphaNum(dst []byte) {
+	g.FillChars(dst, LowerAlphaNumCharset)
+}
+
+// AppendLowerAlpha appends n randomly generated characters using a-z to dst
+// and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendLowerAlpha(dst []byte, n int) []byte {
+	return g.AppendChars(dst, LowerAlphaCharset, n)
+}
+
+// FillLowerAlpha fills dst with randomly generated characters using a-z.
+func (g *Generator) FillLowerAlpha(dst []byte) {
+	g.FillChars(dst, LowerAlphaCharset)
+}
+
+// AppendUpperAlphaNum appends n randomly generated characters using A-Z0-9 to
+// dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendUpperAlphaNum(dst []byte, n int) []byte {
+	return g.AppendChars(dst, UpperAlphaNumCharset, n)
+}
+
+// FillUpperAlphaNum fills dst with randomly generated characters using A-Z0-9.
+func (g *Generator) FillUpperAlphaNum(dst []byte) {
+	g.FillChars(dst, UpperAlphaNumCharset)
+}
+
+// AppendUpperAlpha appends n randomly generated characters using A-Z to dst
+// and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendUpperAlpha(dst []byte, n int) []byte {
+	return g.AppendChars(dst, UpperAlphaCharset, n)
+}
+
+// FillUpperAlpha fills dst with randomly generated characters using A-Z.
+func (g *Generator) FillUpperAlpha(dst []byte) {
+	g.FillChars(dst, UpperAlphaCharset)
+}
+
+// AppendBase64 appends n randomly generated characters using Base64, as
+// defined in Table 1 of RFC 4648, to dst and returns the extended slice. This
+// will panic if n < 0.
+func (g *Generator) AppendBase64(dst []byte, n int) []byte {
+	return g.AppendChars(dst, Base64Charset, n)
+}
+
+// FillBase64 fills dst with randomly generated characters using Base64, as
+// defined in Table 1 of RFC 4648.
+func (g *Generator) FillBase64(dst []byte) {
+	g.FillChars(dst, Base64Charset)
+}
+
+// AppendBase64URL appends n randomly generated characters using Base64URL, as
+// defined in Table 2 of RFC 4648, to dst and returns the extended slice. This
+// will panic if n < 0.
+func (g *Generator) AppendBase64URL(dst []byte, n int) []byte {
+	return g.AppendChars(dst, Base64URLCharset, n)
+}
+
+// FillBase64URL fills dst with randomly generated characters using Base64URL,
+// as defined in Table 2 of RFC 4648.
+func (g *Generator) FillBase64URL(dst []byte) {
+	g.FillChars(dst, Base64URLCharset)
+}
+
+// AppendDigits appends n randomly generated characters using 0-9 to dst and
+// returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendDigits(dst []byte, n int) []byte {
+	return g.AppendChars(dst, DigitsCharset, n)
+}
+
+// FillDigits fills dst with randomly generated characters using 0-9.
+func (g *Generator) FillDigits(dst []byte) {
+	g.FillChars(dst, DigitsCharset)
+}
+
+// AppendLowerHex appends n randomly generated characters using 0-9a-f to dst
+// and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendLowerHex(dst []byte, n int) []byte {
+	return g.AppendChars(dst, LowerHexCharset, n)
+}
+
+// FillLowerHex fills dst with randomly generated characters using 0-9a-f.
+func (g *Generator) FillLowerHex(dst []byte) {
+	g.FillChars(dst, LowerHexCharset)
+}
+
+// AppendUpperHex appends n randomly generated characters using 0-9A-F to dst
+// and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendUpperHex(dst []byte, n int) []byte {
+	return g.AppendChars(dst, UpperHexCharset, n)
+}
+
+// FillUpperHex fills dst with randomly generated characters using 0-9A-F.
+func (g *Generator) FillUpperHex(dst []byte) {
+	g.FillChars(dst, UpperHexCharset)
+}
+
+// AppendZ85 appends n randomly generated characters using Z85 to dst and
+// returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendZ85(dst []byte, n int) []byte {
+	return g.AppendChars(dst, Z85Charset, n)
+}
+
+// FillZ85 fills dst with randomly generated characters using Z85.
+func (g *Generator) FillZ85(dst []byte) {
+	g.FillChars(dst, Z85Charset)
+}
+
+// AppendAscii85 appends n randomly generated characters using Ascii85 to dst
+// and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendAscii85(dst []byte, n int) []byte {
+	return g.AppendChars(dst, Ascii85Charset, n)
+}
+
+// FillAscii85 fills dst with randomly generated characters using Ascii85.
+func (g *Generator) FillAscii85(dst []byte) {
+	g.FillChars(dst, Ascii85Charset)
+}
+
+// AppendPrintable appends n randomly generated printable ASCII characters to
+// dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendPrintable(dst []byte, n int) []byte {
+	return g.AppendChars(dst, PrintableCharset, n)
+}
+
+// FillPrintable fills dst with randomly generated printable ASCII characters.
+func (g *Generator) FillPrintable(dst []byte) {
+	g.FillChars(dst, PrintableCharset)
+}
+
+// AppendShellSafe appends n randomly generated characters using
+// ShellSafeCharset to dst and returns the extended slice. This will panic if n
+// < 0.
+func (g *Generator) AppendShellSafe(dst []byte, n int) []byte {
+	return g.AppendChars(dst, ShellSafeCharset, n)
+}
+
+// FillShellSafe fills dst with randomly generated characters using
+// ShellSafeCharset.
+func (g *Generator) FillShellSafe(dst []byte) {
+	g.FillChars(dst, ShellSafeCharset)
+}
+
+// AppendURLPath appends n randomly generated characters using URLPathCharset
+// to dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendURLPath(dst []byte, n int) []byte {
+	return g.AppendChars(dst, URLPathCharset, n)
+}
+
+// FillURLPath fills dst with randomly generated characters using
+// URLPathCharset.
+func (g *Generator) FillURLPath(dst []byte) {
+	g.FillChars(dst, URLPathCharset)
+}
+
+// AppendJSONSafe appends n randomly generated characters using JSONSafeCharset
+// to dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendJSONSafe(dst []byte, n int) []byte {
+	return g.AppendChars(dst, JSONSafeCharset, n)
+}
+
+// FillJSONSafe fills dst with randomly generated characters using
+// JSONSafeCharset.
+func (g *Generator) FillJSONSafe(dst []byte) {
+	g.FillChars(dst, JSONSafeCharset)
+}
+
+// AppendXMLAttr appends n randomly generated characters using XMLAttrCharset
+// to dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendXMLAttr(dst []byte, n int) []byte {
+	return g.AppendChars(dst, XMLAttrCharset, n)
+}
+
+// FillXMLAttr fills dst with randomly generated characters using
+// XMLAttrCharset.
+func (g *Generator) FillXMLAttr(dst []byte) {
+	g.FillChars(dst, XMLAttrCharset)
+}
+
+// AppendDSNPassword appends n randomly generated characters using
+// DSNPasswordCharset to dst and returns the extended slice. This will panic if
+// n < 0.
+func (g *Generator) AppendDSNPassword(dst []byte, n int) []byte {
+	return g.AppendChars(dst, DSNPasswordCharset, n)
+}
+
+// FillDSNPassword fills dst with randomly generated characters using
+// DSNPasswordCharset.
+func (g *Generator) FillDSNPassword(dst []byte) {
+	g.FillChars(dst, DSNPasswordCharset)
+}
+
+// AppendBase32 appends n randomly generated characters using Base32, as
+// defined in Table 3 of RFC 4648, to dst and returns the extended slice. This
+// will panic if n < 0.
+func (g *Generator) AppendBase32(dst []byte, n int) []byte {
+	return g.AppendChars(dst, Base32Charset, n)
+}
+
+// FillBase32 fills dst with randomly generated characters using Base32, as
+// defined in Table 3 of RFC 4648.
+func (g *Generator) FillBase32(dst []byte) {
+	g.FillChars(dst, Base32Charset)
+}
+
+// AppendBase32Hex appends n randomly generated characters using Base32hex, as
+// defined in Table 4 of RFC 4648, to dst and returns the extended slice. This
+// will panic if n < 0.
+func (g *Generator) AppendBase32Hex(dst []byte, n int) []byte {
+	return g.AppendChars(dst, Base32HexCharset, n)
+}
+
+// FillBase32Hex fills dst with randomly generated characters using Base32hex,
+// as defined in Table 4 of RFC 4648.
+func (g *Generator) FillBase32Hex(dst []byte) {
+	g.FillChars(dst, Base32HexCharset)
+}
+
+// AppendCrockford appends n randomly generated characters using Crockford's
+// Base32 to dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendCrockford(dst []byte, n int) []byte {
+	return g.AppendChars(dst, CrockfordCharset, n)
+}
+
+// FillCrockford fills dst with randomly generated characters using
+// Crockford's Base32.
+func (g *Generator) FillCrockford(dst []byte) {
+	g.FillChars(dst, CrockfordCharset)
+}
+
+// AppendBase58 appends n randomly generated characters using Bitcoin's Base58
+// alphabet to dst and returns the extended slice. This will panic if n < 0.
+func (g *Generator) AppendBase58(dst []byte, n int) []byte {
+	return g.AppendChars(dst, Base58Charset, n)
+}
+
+// FillBase58 fills dst with randomly generated characters using Bitcoin's
+// Base58 alphabet.
+func (g *Generator) FillBase58(dst []byte) {
+	g.FillChars(dst, Base58Charset)
+}
+
+// AppendBase58Flickr appends n randomly generated characters using Flickr's
+// Base58 alphabet to dst and returns the extended slice. This will panic if
+// n < 0.
+func (g *Generator) AppendBase58Flickr(dst []byte, n int) []byte {
+	return g.AppendChars(dst, Base58FlickrCharset, n)
+}
+
+// FillBase58Flickr fills dst with randomly generated characters using
+// Flickr's Base58 alphabet.
+func (g *Generator) FillBase58Flickr(dst []byte) {
+	g.FillChars(dst, Base58FlickrCharset)
+}
+
+// Chars returns a randomly generated []byte of length n using the characters
+// in cs. This will panic if n < 0 or cs is empty.
+func Chars(cs Charset, n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Chars(cs, n)
+}
+
+// IntN returns an unbiased random int in [0, n). This will panic if n <= 0.
+func IntN(n int) int {
+	g := gen.get()
+	defer gen.put(g)
+	return g.IntN(n)
+}
+
+// Generate returns a randomly generated []byte of length n using the
+// characters in cs. Unlike Chars, an error is returned instead of panicking:
+// ErrNegativeLength if n < 0, ErrEmptyCharset if cs is empty, and an error
+// wrapping ErrEntropy if a generator couldn't be seeded from the CSPRNG.
+func Generate(cs Charset, n int) ([]byte, error) {
+	g, err := gen.tryGet()
+	if err != nil {
+		return nil, err
+	}
+	defer gen.put(g)
+	return g.Generate(cs, n)
+}
+
+// GenerateRunes returns n randomly generated runes using the runes in cs.
+// Unlike Runes, an error is returned instead of panicking: ErrNegativeLength
+// if n < 0, ErrEmptyCharset if cs is empty, and an error wrapping ErrEntropy
+// if a generator couldn't be seeded from the CSPRNG.
+func GenerateRunes(cs RuneCharset, n int) ([]rune, error) {
+	g, err := gen.tryGet()
+	if err != nil {
+		return nil, err
+	}
+	defer gen.put(g)
+	return g.GenerateRunes(cs, n)
+}
+
+// Runes returns n randomly generated runes using the runes in cs. This will
+// panic if n < 0 or cs is empty.
+func Runes(cs RuneCharset, n int) []rune {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Runes(cs, n)
+}
+
+// RuneString returns a string of n randomly generated runes using the runes
+// in cs. This will panic if n < 0 or cs is empty.
+func RuneString(cs RuneCharset, n int) string {
+	g := gen.get()
+	defer gen.put(g)
+	return g.RuneString(cs, n)
 }
 
 // AlphaNum returns a randomly generated []byte of length n using a-zA-Z0-9.
 // This will panic if n < 0.
 func AlphaNum(n int) []byte {
-	mu.Lock()
-	defer mu.Unlock()
-	return gen.AlphaNum(n)
+	g := gen.get()
+	defer gen.put(g)
+	return g.AlphaNum(n)
 }
 
 // Alpha returns a randomly generated []byte of length n using a-zA-Z. This
 // will panic if n < 0.
 func Alpha(n int) []byte {
-	mu.Lock()
-	defer mu.Unlock()
-	return gen.Alpha(n)
+	g := gen.get()
+	defer gen.put(g)
+	return g.Alpha(n)
 }
 
 // LowerAlphaNum returns a randomly generated []byte of length n using a-z0-9.
 // This will panic if n < 0.
 func LowerAlphaNum(n int) []byte {
-	mu.Lock()
-	defer mu.Unlock()
-	return gen.LowerAlphaNum(n)
+	g := gen.get()
+	defer gen.put(g)
+	return g.LowerAlphaNum(n)
 }
 
 // LowerAlpha returns a randomly generated []byte of length n using a-z. This
 // will panic if n < 0.
 func LowerAlpha(n int) []byte {
-	mu.Lock()
-	defer mu.Unlock()
-	return gen.LowerAlpha(n)
+	g := gen.get()
+	defer gen.put(g)
+	return g.LowerAlpha(n)
 }
 
 // UpperAlphaNum returns a randomly generated []byte of length n using A-Z0-9.
 // This will panic if n < 0.
 func UpperAlphaNum(n int) []byte {
-	mu.Lock()
-	defer mu.Unlock()
-	return gen.UpperAlphaNum(n)
+	g := gen.get()
+	defer gen.put(g)
+	return g.UpperAlphaNum(n)
 }
 
 // UpperAlpha returns a randomly generated []byte of length n using A-Z. This
 // will panic if n < 0.
 func UpperAlpha(n int) []byte {
-	mu.Lock()
-	defer mu.Unlock()
-	return gen.UpperAlpha(n)
+	g := gen.get()
+	defer gen.put(g)
+	return g.UpperAlpha(n)
 }
 
 // Base64 returns a randomly generated []byte of length n using base64, as
 // defined in Table 1 of RFC 4648. This will panic if n < 0.
 func Base64(n int) []byte {
-	mu.Lock()
-	defer mu.Unlock()
-	return gen.Base64(n)
+	g := gen.get()
+	defer gen.put(g)
+	return g.Base64(n)
 }
 
 // Base64URL returns a randomly generated []byte of length n using base64url,
 // as defined in Table 2 of RFC 4648 filename safe base64. This will panic if
 // n < 0.
 func Base64URL(n int) []byte {
-	mu.Lock()
-	defer mu.Unlock()
-	return gen.Base64URL(n)
+	g := gen.get()
+	defer gen.put(g)
+	return g.Base64URL(n)
+}
+
+// Digits returns a randomly generated []byte of length n using 0-9. This will
+// panic if n < 0.
+func Digits(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Digits(n)
+}
+
+// LowerHex returns a randomly generated []byte of length n using 0-9a-f. This
+// will panic if n < 0.
+func LowerHex(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.LowerHex(n)
+}
+
+// UpperHex returns a randomly generated []byte of length n using 0-9A-F. This
+// will panic if n < 0.
+func UpperHex(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.UpperHex(n)
+}
+
+// CharsNoLeadingZero returns a randomly generated []byte of length n using
+// the characters in cs whose first character isn't '0'. This will panic if
+// n < 0 or cs has no characters other than '0'.
+func CharsNoLeadingZero(cs Charset, n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.CharsNoLeadingZero(cs, n)
+}
+
+// Z85 returns a randomly generated []byte of length n using Z85. This will
+// panic if n < 0.
+func Z85(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Z85(n)
+}
+
+// Ascii85 returns a randomly generated []byte of length n using Ascii85. This
+// will panic if n < 0.
+func Ascii85(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Ascii85(n)
+}
+
+// Printable returns a randomly generated []byte of length n using the
+// printable ASCII characters. This will panic if n < 0.
+func Printable(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Printable(n)
+}
+
+// ShellSafe returns a randomly generated []byte of length n using
+// ShellSafeCharset. This will panic if n < 0.
+func ShellSafe(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.ShellSafe(n)
+}
+
+// URLPath returns a randomly generated []byte of length n using
+// URLPathCharset. This will panic if n < 0.
+func URLPath(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.URLPath(n)
+}
+
+// JSONSafe returns a randomly generated []byte of length n using
+// JSONSafeCharset. This will panic if n < 0.
+func JSONSafe(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.JSONSafe(n)
+}
+
+// XMLAttr returns a randomly generated []byte of length n using
+// XMLAttrCharset. This will panic if n < 0.
+func XMLAttr(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.XMLAttr(n)
+}
+
+// DSNPassword returns a randomly generated []byte of length n using
+// DSNPasswordCharset. This will panic if n < 0.
+func DSNPassword(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.DSNPassword(n)
+}
+
+// Base32 returns a randomly generated []byte of length n using Base32, as
+// defined in Table 3 of RFC 4648. This will panic if n < 0.
+func Base32(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Base32(n)
+}
+
+// Base32Hex returns a randomly generated []byte of length n using Base32hex,
+// as defined in Table 4 of RFC 4648. This will panic if n < 0.
+func Base32Hex(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Base32Hex(n)
+}
+
+// Crockford returns a randomly generated []byte of length n using Crockford's
+// Base32. This will panic if n < 0.
+func Crockford(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Crockford(n)
+}
+
+// CrockfordWithCheck returns n randomly generated characters using
+// Crockford's Base32 followed by their check symbol. This will panic if
+// n < 1.
+func CrockfordWithCheck(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.CrockfordWithCheck(n)
+}
+
+// Base58 returns a randomly generated []byte of length n using Bitcoin's
+// Base58 alphabet. This will panic if n < 0.
+func Base58(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Base58(n)
+}
+
+// Base58Flickr returns a randomly generated []byte of length n using
+// Flickr's Base58 alphabet. This will panic if n < 0.
+func Base58Flickr(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Base58Flickr(n)
 }
 
-// Base64 supports the Base 64 Alphabet as shown in Table 1 of RFC 4248. This
-// uses an implementation of the XORoShiRo128+ PRNG:
-// http://xoroshiro.di.unimi.it/.
+// Base58Check returns the Base58Check encoding of a payload of n random
+// bytes; see EncodeBase58Check. This will panic if n < 0.
+func Base58Check(n int) []byte {
+	g := gen.get()
+	defer gen.put(g)
+	return g.Base58Check(n)
+}
+
+// Base64 supports the Base 64 Alphabet as shown in Table 1 of RFC 4248.
+// Unless another Source is specified, this uses an implementation of the
+// XORoShiRo128+ PRNG: http://xoroshiro.di.unimi.it/.
 //
 // This is more performant than using Generator for base64.
 type Base64Generator struct {
-	rng xoro.State
+	src Source
 }
 
 // NewBase64 returns an initialized Base64Generator that is ready to use. The
 // seed value used is an int64 obtained from a CSPRNG.
 func NewBase64Generator() *Base64Generator {
-	return &Base64Generator{xoro.New(Int64())}
+	return &Base64Generator{NewXoroshiroSource(Int64())}
 }
 
 // NewBase64GeneratorWithSeed a Base64Generator using the received value as
 // its seed.
 func NewBase64GeneratorWithSeed(seed int64) *Base64Generator {
-	return &Base64Generator{xoro.New(seed)}
+	return &Base64Generator{NewXoroshiroSource(seed)}
+}
+
+// NewBase64GeneratorWithSource returns a Base64Generator that uses src
+// instead of XORoShiRo128+.
+func NewBase64GeneratorWithSource(src Source) *Base64Generator {
+	return &Base64Generator{src}
 }
 
 // Seed seeds Base64Generator's prng using the provided value.
 func (g *Base64Generator) Seed(n int64) {
-	g.rng.Seed(n)
+	g.src.Seed(n)
 }
 
 // Reseed seeds Base64Generator's prng using a value obtained from a CSPRNG.
 func (g *Base64Generator) Reseed() {
-	g.rng.Seed(Int64())
+	g.src.Seed(Int64())
 }
 
 // Bytes returns n randomly generated Base 64 bytes.
@@ -325,64 +1214,104 @@ func (g *Base64Generator) Bytes(n int) []byte {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	id := make([]byte, 0, n)
-	for i := 0; i < n; i++ {
-		id = append(id, base64[g.rng.Int63n(int64(64))])
+	b := make([]byte, n)
+	g.Fill(b)
+	return b
+}
+
+// Generate returns n randomly generated Base 64 bytes. Unlike Bytes,
+// ErrNegativeLength is returned if n < 0 instead of panicking.
+func (g *Base64Generator) Generate(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, ErrNegativeLength
+	}
+	return g.Bytes(n), nil
+}
+
+// Append appends n randomly generated Base 64 bytes to dst and returns the
+// extended slice. If dst has sufficient capacity, no allocation is done. This
+// will panic if n < 0.
+func (g *Base64Generator) Append(dst []byte, n int) []byte {
+	if n < 0 {
+		panic(fmt.Sprintf("%d: value out of bounds", n))
+	}
+	l := len(dst)
+	dst = slices.Grow(dst, n)[:l+n]
+	g.Fill(dst[l:])
+	return dst
+}
+
+// Fill fills dst with randomly generated Base 64 bytes. Each draw from the
+// Source provides 10 characters from its high 60 bits; XORoShiRo128+'s low
+// bits are weak.
+func (g *Base64Generator) Fill(dst []byte) {
+	for len(dst) > 0 {
+		r := g.src.Uint64() >> 4
+		n := 10
+		if n > len(dst) {
+			n = len(dst)
+		}
+		for j := 0; j < n; j++ {
+			dst[j] = base64[r&63]
+			r >>= 6
+		}
+		dst = dst[n:]
 	}
-	return id
 }
 
-// SeedBase64 seeds Base64Generator's prng using the provided value.
+// SeedBase64 seeds Base64Bytes' prng using the provided value; see Seed.
 func SeedBase64(n int64) {
-	mu64.Lock()
-	genBase64.Seed(n)
-	mu64.Unlock()
+	genBase64.seed(NewBase64GeneratorWithSeed(n))
 }
 
-// ReseedBase64 seeds Base64Generator's prng using a value obtained from a
-// CSPRNG.
+// ReseedBase64 seeds Base64Bytes' prng using values obtained from a CSPRNG;
+// see ReSeed.
 func ReseedBase64() {
-	mu64.Lock()
-	genBase64.Reseed()
-	mu64.Unlock()
+	genBase64.seed(nil)
 }
 
 // Base64Bytes returns n randomly generated Base 64 bytes.
 func Base64Bytes(n int) []byte {
-	mu64.Lock()
-	defer mu64.Unlock()
-	return genBase64.Bytes(n)
+	g := genBase64.get()
+	defer genBase64.put(g)
+	return g.Bytes(n)
 }
 
 // Base64URL supports the Base64URL Alphabet as shown in Table 2 of RFC 4248.
-// This uses an implementation of the XORoShiRo128+ PRNG:
-// http://xoroshiro.di.unimi.it/.
+// Unless another Source is specified, this uses an implementation of the
+// XORoShiRo128+ PRNG: http://xoroshiro.di.unimi.it/.
 //
 // This is more performant than using Generator for base64url.
 type Base64URLGenerator struct {
-	rng xoro.State
+	src Source
 }
 
 // NewBase64URL returns an initialized Base64GeneratorURL that is ready to use.
 // The seed value used is an int64 obtained from a CSPRNG.
 func NewBase64URLGenerator() *Base64URLGenerator {
-	return &Base64URLGenerator{xoro.New(Int64())}
+	return &Base64URLGenerator{NewXoroshiroSource(Int64())}
 }
 
 // NewBase64URLGeneratorWithSeed a Base64GeneratorURL using the received value
 // as its seed.
 func NewBase64URLGeneratorWithSeed(seed int64) *Base64URLGenerator {
-	return &Base64URLGenerator{xoro.New(seed)}
+	return &Base64URLGenerator{NewXoroshiroSource(seed)}
+}
+
+// NewBase64URLGeneratorWithSource returns a Base64URLGenerator that uses src
+// instead of XORoShiRo128+.
+func NewBase64URLGeneratorWithSource(src Source) *Base64URLGenerator {
+	return &Base64URLGenerator{src}
 }
 
 // Seed seeds Base64URLGenerator's prng using the provided value.
 func (g *Base64URLGenerator) Seed(n int64) {
-	g.rng.Seed(n)
+	g.src.Seed(n)
 }
 
 // Reseed seeds Base64URLGenerator's prng using a value obtained from a CSPRNG.
 func (g *Base64URLGenerator) Reseed() {
-	g.rng.Seed(Int64())
+	g.src.Seed(Int64())
 }
 
 // Bytes returns n randomly generated Base64URL bytes.
@@ -390,41 +1319,94 @@ func (g *Base64URLGenerator) Bytes(n int) []byte {
 	if n < 0 {
 		panic(fmt.Sprintf("%d: value out of bounds", n))
 	}
-	id := make([]byte, 0, n)
-	for i := 0; i < n; i++ {
-		id = append(id, base64[g.rng.Int63n(int64(64))])
+	b := make([]byte, n)
+	g.Fill(b)
+	return b
+}
+
+// Generate returns n randomly generated Base64URL bytes. Unlike Bytes,
+// ErrNegativeLength is returned if n < 0 instead of panicking.
+func (g *Base64URLGenerator) Generate(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, ErrNegativeLength
+	}
+	return g.Bytes(n), nil
+}
+
+// Append appends n randomly generated Base64URL bytes to dst and returns the
+// extended slice. If dst has sufficient capacity, no allocation is done. This
+// will panic if n < 0.
+func (g *Base64URLGenerator) Append(dst []byte, n int) []byte {
+	if n < 0 {
+		panic(fmt.Sprintf("%d: value out of bounds", n))
+	}
+	l := len(dst)
+	dst = slices.Grow(dst, n)[:l+n]
+	g.Fill(dst[l:])
+	return dst
+}
+
+// Fill fills dst with randomly generated Base64URL bytes. Each draw from the
+// Source provides 10 characters from its high 60 bits; XORoShiRo128+'s low
+// bits are weak.
+func (g *Base64URLGenerator) Fill(dst []byte) {
+	for len(dst) > 0 {
+		r := g.src.Uint64() >> 4
+		n := 10
+		if n > len(dst) {
+			n = len(dst)
+		}
+		for j := 0; j < n; j++ {
+			dst[j] = base64URL[r&63]
+			r >>= 6
+		}
+		dst = dst[n:]
 	}
-	return id
 }
 
-// SeedBase64URL seeds Base64URLGenerator's prng using the provided value.
+// SeedBase64URL seeds Base64URLBytes' prng using the provided value; see
+// Seed.
 func SeedBase64URL(n int64) {
-	mu64URL.Lock()
-	genBase64URL.Seed(n)
-	mu64URL.Unlock()
+	genBase64URL.seed(NewBase64URLGeneratorWithSeed(n))
 }
 
-// ReseedBase64URL seeds Base64URLGenerator's prng using a value obtained from
-// a CSPRNG.
+// ReseedBase64URL seeds Base64URLBytes' prng using values obtained from a
+// CSPRNG; see ReSeed.
 func ReseedBase64URL() {
-	mu64URL.Lock()
-	genBase64URL.Reseed()
-	mu64URL.Unlock()
+	genBase64URL.seed(nil)
 }
 
 // Base64URLBytes returns n randomly generated Base64URL bytes.
 func Base64URLBytes(n int) []byte {
-	mu64URL.Lock()
-	defer mu64URL.Unlock()
-	return genBase64URL.Bytes(n)
+	g := genBase64URL.get()
+	defer genBase64URL.put(g)
+	return g.Bytes(n)
 }
 
-// Int64 gets an int64 value from a CSPRNG.
+// Int64 gets an int64 value from a CSPRNG. This will panic if a value could
+// not be read from the CSPRNG; use ReadInt64 to get an error instead.
 func Int64() int64 {
+	n, err := ReadInt64()
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// ReadInt64 gets an int64 value from a CSPRNG. If a value could not be read,
+// the returned error wraps ErrEntropy. The value can be used to seed a
+// Generator without risking a panic:
+//
+//	seed, err := randchars.ReadInt64()
+//	if err != nil {
+//		return err
+//	}
+//	g := randchars.NewGeneratorWithSeed(seed)
+func ReadInt64() (int64, error) {
 	bi := big.NewInt(1<<63 - 1)
-	r, err := rand.Int(rand.Reader, bi)
+	r, err := rand.Int(reader, bi)
 	if err != nil {
-		panic(fmt.Sprintf("entropy read error: %s", err))
+		return 0, fmt.Errorf("%w: %s", ErrEntropy, err)
 	}
-	return r.Int64()
+	return r.Int64(), nil
 }
@@ -0,0 +1,61 @@
+package randchars
+
+// CharsFiller is implemented by generators that can fill a []byte with random
+// characters from a Charset. Both Generator and crandchars.Generator
+// implement it.
+type CharsFiller interface {
+	FillChars(dst []byte, cs Charset)
+}
+
+// CharsReader is implemented by generators whose source of randomness can
+// fail, e.g. crandchars.Generator. ReadChars is FillChars but returns an
+// error, wrapping ErrEntropy, instead of panicking; dst must be discarded if
+// it does.
+type CharsReader interface {
+	ReadChars(dst []byte, cs Charset) error
+}
+
+// readChars fills dst with characters from cs using g, returning an error
+// instead of panicking if g is a CharsReader.
+func readChars(g CharsFiller, dst []byte, cs Charset) error {
+	if r, ok := g.(CharsReader); ok {
+		return r.ReadChars(dst, cs)
+	}
+	g.FillChars(dst, cs)
+	return nil
+}
+
+// Reader is an io.Reader whose Read fills p with random characters. Reads do
+// not allocate and only fail if the generator is a CharsReader whose
+// ReadChars fails, e.g. crandchars.Generator when the CSPRNG fails. A Reader
+// is not safe for concurrent use.
+type Reader struct {
+	fill func(p []byte) error
+}
+
+// NewReader returns a Reader that uses g to generate characters from cs. This
+// will panic if cs is empty.
+func NewReader(g CharsFiller, cs Charset) *Reader {
+	if cs.Len() == 0 {
+		panic(ErrEmptyCharset)
+	}
+	return &Reader{fill: func(p []byte) error { return readChars(g, p, cs) }}
+}
+
+// NewBase64Reader returns a Reader that uses g to generate Base 64
+// characters.
+func NewBase64Reader(g *Base64Generator) *Reader {
+	return &Reader{fill: func(p []byte) error {
+		g.Fill(p)
+		return nil
+	}}
+}
+
+// Read fills p with random characters. It returns len(p), nil unless the
+// generator fails, in which case it returns 0 and the error.
+func (r *Reader) Read(p []byte) (n int, err error) {
+	if err := r.fill(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
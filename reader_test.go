@@ -0,0 +1,67 @@
+package randchars
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	// Characters left over from a draw are discarded at the end of each Read,
+	// so a single Read matches Chars.
+	g := NewGeneratorWithSeed(0)
+	expected := g.Chars(LowerAlphaNumCharset, 1000)
+	g.Seed(0)
+	buf := make([]byte, 1000)
+	n, err := NewReader(g, LowerAlphaNumCharset).Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 1000 {
+		t.Errorf("got %d bytes; want 1000", n)
+	}
+	if string(buf) != string(expected) {
+		t.Errorf("got %q; want %q", buf, expected)
+	}
+	// and a Reader works with io.Copy
+	var copied bytes.Buffer
+	if n, err := io.Copy(&copied, io.LimitReader(NewReader(g, LowerAlphaNumCharset), 1000)); err != nil || n != 1000 {
+		t.Errorf("got %d, %v; want 1000, nil", n, err)
+	}
+	for _, c := range copied.Bytes() {
+		if !LowerAlphaNumCharset.Contains(c) {
+			t.Errorf("%q: not in %q", c, LowerAlphaNumCharset)
+		}
+	}
+
+	r := bufio.NewReader(NewBase64Reader(NewBase64GeneratorWithSeed(0)))
+	b := make([]byte, 10)
+	_, err = io.ReadFull(r, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(b) != "6mxZbPgLju" {
+		t.Errorf("got %q; want %q", string(b), "6mxZbPgLju")
+	}
+}
+
+func TestReaderAllocs(t *testing.T) {
+	r := NewReader(NewGenerator(), AlphaNumCharset)
+	p := make([]byte, 64)
+	allocs := testing.AllocsPerRun(100, func() { r.Read(p) })
+	if allocs != 0 {
+		t.Errorf("got %v allocs per Read; want 0", allocs)
+	}
+}
+
+func BenchmarkReader_4096(b *testing.B) {
+	r := NewReader(NewGenerator(), AlphaNumCharset)
+	p := make([]byte, 4096)
+	b.SetBytes(int64(len(p)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Read(p)
+	}
+}
@@ -0,0 +1,352 @@
+// Package regen generates random strings that match a regular expression,
+// e.g. for property-based tests of validators.
+//
+// The expression is parsed with regexp/syntax, using the same syntax as the
+// regexp package, and compiled once, when the Generator is created. Character
+// classes, alternation, grouping, and repetition are supported. Unbounded
+// repetitions, *, +, and {n,}, are capped at the Generator's maximum repeat.
+// Anchors and word boundaries, e.g. ^, $, and \b, are not supported as they
+// constrain the surrounding text rather than generate it.
+//
+// Any character, ., and the classes that extend to unicode.MaxRune, e.g.
+// negated classes like [^a-z], \D, and \W, would mostly generate unassigned
+// or non-printable code points. Their runes are limited to the Generator's
+// any-char runes, DefaultAnyChar unless it is created with NewWithAnyChar; a
+// class with none of them keeps all of its runes.
+//
+// Every random choice, a character from a class, an alternative, or a
+// repetition count, is uniform and uses a single IntN call of the
+// randchars.IntNer the Generator was created with. With a seeded
+// randchars.Generator the strings are reproducible test cases: the same seed
+// and expression generate the same strings.
+package regen
+
+import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mohae/randchars"
+)
+
+// DefaultMaxRepeat is the maximum number of repetitions generated for *, +,
+// and {n,} when a Generator is created with New.
+const DefaultMaxRepeat = 10
+
+// DefaultAnyChar are the any-char runes, printable ASCII, when a Generator is
+// created with New or NewWithMaxRepeat.
+var DefaultAnyChar = &unicode.RangeTable{R16: []unicode.Range16{{Lo: ' ', Hi: '~', Stride: 1}}}
+
+// ErrUnsatisfiable is returned when no string can match an expression, e.g.
+// one with an empty character class.
+var ErrUnsatisfiable = errors.New("regen: expression cannot match any string")
+
+type op uint8
+
+const (
+	opLiteral op = iota
+	opClass
+	opConcat
+	opAlternate
+	opRepeat
+)
+
+// node is a compiled regexp/syntax.Regexp.
+type node struct {
+	op op
+	// runes is the opLiteral's runes.
+	runes []rune
+	// ranges is the opClass's runes as inclusive lo, hi pairs; it doesn't
+	// contain any surrogates. n is the number of runes in the ranges.
+	ranges []rune
+	n      int
+	// subs is the opConcat's and opAlternate's sub-expressions; an opRepeat
+	// has one.
+	subs     []*node
+	min, max int
+}
+
+// Generator generates strings that match an expression. A Generator is not
+// safe for concurrent use unless its randchars.IntNer is.
+type Generator struct {
+	r         randchars.IntNer
+	expr      string
+	maxRepeat int
+	// anyChar are the any-char runes as inclusive lo, hi pairs; nil means
+	// all of Unicode.
+	anyChar []rune
+	prog    *node
+}
+
+// New returns a Generator that uses r to generate strings matching expr. An
+// error is returned if expr is invalid, uses an unsupported construct, or
+// cannot match any string.
+func New(r randchars.IntNer, expr string) (*Generator, error) {
+	return NewWithMaxRepeat(r, expr, DefaultMaxRepeat)
+}
+
+// NewWithMaxRepeat is like New but generates at most max repetitions for *,
+// +, and {n,}; {n,} always generates at least n. Bounded repetitions, e.g.
+// {2,20}, are not affected.
+func NewWithMaxRepeat(r randchars.IntNer, expr string, max int) (*Generator, error) {
+	return NewWithAnyChar(r, expr, max, DefaultAnyChar)
+}
+
+// NewWithAnyChar is like NewWithMaxRepeat but uses the runes in anyChar as
+// the any-char runes; if anyChar is nil, all of Unicode, except the
+// surrogates, is used.
+func NewWithAnyChar(r randchars.IntNer, expr string, max int, anyChar *unicode.RangeTable) (*Generator, error) {
+	if max < 0 {
+		return nil, fmt.Errorf("regen: %d: maximum repeat must be >= 0", max)
+	}
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("regen: %w", err)
+	}
+	g := &Generator{r: r, expr: expr, maxRepeat: max}
+	if anyChar != nil {
+		g.anyChar = tablePairs(anyChar)
+	}
+	g.prog, err = g.compile(re)
+	if err != nil {
+		return nil, err
+	}
+	if g.prog == nil {
+		return nil, ErrUnsatisfiable
+	}
+	return g, nil
+}
+
+// compile returns the node for re; a nil node means re cannot match any
+// string.
+func (g *Generator) compile(re *syntax.Regexp) (*node, error) {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return nil, nil
+	case syntax.OpEmptyMatch:
+		return &node{op: opConcat}, nil
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase == 0 {
+			return &node{op: opLiteral, runes: re.Rune}, nil
+		}
+		n := &node{op: opConcat}
+		for _, r := range re.Rune {
+			n.subs = append(n.subs, newClass(fold(r)))
+		}
+		return n, nil
+	case syntax.OpCharClass:
+		return g.class(re.Rune), nil
+	case syntax.OpAnyCharNotNL:
+		return g.class([]rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune}), nil
+	case syntax.OpAnyChar:
+		return g.class([]rune{0, unicode.MaxRune}), nil
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		return nil, fmt.Errorf("regen: %q: anchors are not supported", g.expr)
+	case syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return nil, fmt.Errorf("regen: %q: word boundaries are not supported", g.expr)
+	case syntax.OpCapture:
+		return g.compile(re.Sub[0])
+	case syntax.OpStar:
+		return g.repeat(re.Sub[0], 0, -1)
+	case syntax.OpPlus:
+		return g.repeat(re.Sub[0], 1, -1)
+	case syntax.OpQuest:
+		return g.repeat(re.Sub[0], 0, 1)
+	case syntax.OpRepeat:
+		return g.repeat(re.Sub[0], re.Min, re.Max)
+	case syntax.OpConcat:
+		n := &node{op: opConcat}
+		for _, sub := range re.Sub {
+			s, err := g.compile(sub)
+			if err != nil || s == nil {
+				return nil, err
+			}
+			n.subs = append(n.subs, s)
+		}
+		return n, nil
+	case syntax.OpAlternate:
+		// alternatives that can't match anything are dropped
+		n := &node{op: opAlternate}
+		for _, sub := range re.Sub {
+			s, err := g.compile(sub)
+			if err != nil {
+				return nil, err
+			}
+			if s != nil {
+				n.subs = append(n.subs, s)
+			}
+		}
+		if len(n.subs) == 0 {
+			return nil, nil
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("regen: %q: %s is not supported", g.expr, re)
+}
+
+// repeat returns the node for min to max repetitions of re; a negative max
+// means there is no maximum.
+func (g *Generator) repeat(re *syntax.Regexp, min, max int) (*node, error) {
+	if max < 0 {
+		max = g.maxRepeat
+		if max < min {
+			max = min
+		}
+	}
+	sub, err := g.compile(re)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		if min > 0 {
+			return nil, nil
+		}
+		// the only match is the empty string
+		return &node{op: opConcat}, nil
+	}
+	return &node{op: opRepeat, subs: []*node{sub}, min: min, max: max}, nil
+}
+
+// class returns the opClass node for the received lo, hi pairs, limited to
+// the any-char runes if they extend to unicode.MaxRune and include any of
+// them.
+func (g *Generator) class(pairs []rune) *node {
+	if g.anyChar != nil && len(pairs) > 0 && pairs[len(pairs)-1] == unicode.MaxRune {
+		if in := intersect(pairs, g.anyChar); len(in) > 0 {
+			pairs = in
+		}
+	}
+	return newClass(pairs)
+}
+
+// tablePairs returns the runes in t as sorted, inclusive, lo, hi pairs.
+func tablePairs(t *unicode.RangeTable) []rune {
+	var pairs []rune
+	add := func(lo, hi, stride rune) {
+		if stride == 1 {
+			pairs = append(pairs, lo, hi)
+			return
+		}
+		for r := lo; r <= hi; r += stride {
+			pairs = append(pairs, r, r)
+		}
+	}
+	for _, r := range t.R16 {
+		add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	for _, r := range t.R32 {
+		add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	return pairs
+}
+
+// intersect returns the runes in both a and b, which are sorted, inclusive,
+// lo, hi pairs, as lo, hi pairs.
+func intersect(a, b []rune) []rune {
+	var pairs []rune
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		lo, hi := max(a[i], b[j]), min(a[i+1], b[j+1])
+		if lo <= hi {
+			pairs = append(pairs, lo, hi)
+		}
+		if a[i+1] < b[j+1] {
+			i += 2
+		} else {
+			j += 2
+		}
+	}
+	return pairs
+}
+
+// newClass returns the opClass node for the received lo, hi pairs; surrogates
+// are removed as they can't be encoded as UTF-8. A class without any runes
+// can't match anything so nil is returned.
+func newClass(pairs []rune) *node {
+	n := &node{op: opClass}
+	add := func(lo, hi rune) {
+		if lo <= hi {
+			n.ranges = append(n.ranges, lo, hi)
+			n.n += int(hi-lo) + 1
+		}
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		lo, hi := pairs[i], pairs[i+1]
+		if hi < surrogateMin || lo > surrogateMax {
+			add(lo, hi)
+			continue
+		}
+		add(lo, surrogateMin-1)
+		add(surrogateMax+1, hi)
+	}
+	if n.n == 0 {
+		return nil
+	}
+	return n
+}
+
+const (
+	surrogateMin = 0xD800
+	surrogateMax = 0xDFFF
+)
+
+// fold returns r and the runes that are equivalent to it under simple case
+// folding, as lo, hi pairs.
+func fold(r rune) []rune {
+	pairs := []rune{r, r}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		pairs = append(pairs, f, f)
+	}
+	return pairs
+}
+
+// String returns the expression the Generator was created with.
+func (g *Generator) String() string {
+	return g.expr
+}
+
+// Generate returns a string that matches the Generator's expression.
+func (g *Generator) Generate() []byte {
+	return g.Append(nil)
+}
+
+// Append appends a string that matches the Generator's expression to dst and
+// returns the extended slice.
+func (g *Generator) Append(dst []byte) []byte {
+	return g.gen(dst, g.prog)
+}
+
+func (g *Generator) gen(dst []byte, n *node) []byte {
+	switch n.op {
+	case opLiteral:
+		for _, r := range n.runes {
+			dst = utf8.AppendRune(dst, r)
+		}
+	case opClass:
+		v := rune(g.r.IntN(n.n))
+		for i := 0; i < len(n.ranges); i += 2 {
+			size := n.ranges[i+1] - n.ranges[i] + 1
+			if v < size {
+				dst = utf8.AppendRune(dst, n.ranges[i]+v)
+				break
+			}
+			v -= size
+		}
+	case opConcat:
+		for _, sub := range n.subs {
+			dst = g.gen(dst, sub)
+		}
+	case opAlternate:
+		dst = g.gen(dst, n.subs[g.r.IntN(len(n.subs))])
+	case opRepeat:
+		count := n.min
+		if n.max > n.min {
+			count += g.r.IntN(n.max - n.min + 1)
+		}
+		for i := 0; i < count; i++ {
+			dst = g.gen(dst, n.subs[0])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,175 @@
+package regen
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mohae/randchars"
+	"github.com/mohae/randchars/crandchars"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{`[a-z]+@[a-z]+\.com`, ""},
+		{`(?i)abc|x{2,5}|[^\x00-\x{D7FF}]`, ""},
+		{`a[^\x00-\x{10FFFF}]?b`, ""},
+		{`a(`, "regen: error parsing regexp: missing closing ): `a(`"},
+		{`^abc$`, "regen: \"^abc$\": anchors are not supported"},
+		{`abc\b`, "regen: \"abc\\\\b\": word boundaries are not supported"},
+		{`a[^\x00-\x{10FFFF}]`, ErrUnsatisfiable.Error()},
+		{`[\x{D800}-\x{DFFF}]`, ErrUnsatisfiable.Error()},
+	}
+	for _, test := range tests {
+		_, err := New(randchars.NewGenerator(), test.expr)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%s: got %q; want %q", test.expr, err, test.err)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%s: got no error; want %q", test.expr, test.err)
+		}
+	}
+	_, err := NewWithMaxRepeat(randchars.NewGenerator(), "a*", -1)
+	if err == nil || err.Error() != "regen: -1: maximum repeat must be >= 0" {
+		t.Errorf("got %v; want a maximum repeat error", err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []string{
+		`abc`,
+		`[a-z]+@[a-z]+\.(com|org|net)`,
+		`\d{3}-\d{4}`,
+		`[[:upper:]][[:lower:]]{2,8}`,
+		`(?i)hello`,
+		`[^a-z]{5}`,
+		`\p{Greek}+`,
+		`.{0,20}`,
+		`(?s).{0,20}`,
+		`(ab|cd)*e?f+`,
+		`x{3,}`,
+		`a[^\x00-\x{10FFFF}]?b`,
+		`[\x{D000}-\x{E000}]{20}`,
+	}
+	for _, r := range []randchars.IntNer{randchars.NewGeneratorWithSeed(0), crandchars.New()} {
+		for _, expr := range tests {
+			g, err := New(r, expr)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %s", expr, err)
+			}
+			re := regexp.MustCompile(`^(?:` + expr + `)$`)
+			for i := 0; i < 200; i++ {
+				b := g.Generate()
+				if !utf8.Valid(b) {
+					t.Fatalf("%s: %q is not valid UTF-8", expr, b)
+				}
+				if !re.Match(b) {
+					t.Fatalf("%s: %q does not match", expr, b)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	const expr = `[A-Z]{3}-\d{4}(-[a-f0-9]{2,6})*`
+	g1, _ := New(randchars.NewGeneratorWithSeed(42), expr)
+	g2, _ := New(randchars.NewGeneratorWithSeed(42), expr)
+	for i := 0; i < 100; i++ {
+		b1, b2 := g1.Generate(), g2.Generate()
+		if !bytes.Equal(b1, b2) {
+			t.Fatalf("got %q and %q; want the same string from the same seed", b1, b2)
+		}
+	}
+}
+
+func TestMaxRepeat(t *testing.T) {
+	tests := []struct {
+		expr     string
+		max      int
+		min, len int
+	}{
+		{`a*`, 0, 0, 0},
+		{`a*`, 5, 0, 5},
+		{`a+`, 5, 1, 5},
+		{`a{3,}`, 5, 3, 5},
+		{`a{8,}`, 5, 8, 8},
+		{`a{2,20}`, 5, 2, 20},
+	}
+	for _, test := range tests {
+		g, err := NewWithMaxRepeat(randchars.NewGeneratorWithSeed(0), test.expr, test.max)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.expr, err)
+		}
+		seen := make(map[int]bool)
+		for i := 0; i < 1000; i++ {
+			n := len(g.Generate())
+			if n < test.min || n > test.len {
+				t.Fatalf("%s: got length %d; want between %d and %d", test.expr, n, test.min, test.len)
+			}
+			seen[n] = true
+		}
+		if len(seen) != test.len-test.min+1 {
+			t.Errorf("%s: got %d distinct lengths; want %d", test.expr, len(seen), test.len-test.min+1)
+		}
+	}
+}
+
+func TestAnyChar(t *testing.T) {
+	tests := []struct {
+		expr    string
+		anyChar *unicode.RangeTable
+		in      func(rune) bool
+	}{
+		{`.{20}`, DefaultAnyChar, func(r rune) bool { return r >= ' ' && r <= '~' }},
+		{`(?s).{20}`, DefaultAnyChar, func(r rune) bool { return r >= ' ' && r <= '~' }},
+		{`[^a-z]{20}`, DefaultAnyChar, func(r rune) bool { return r >= ' ' && r <= '~' && (r < 'a' || r > 'z') }},
+		{`\D{20}`, unicode.Greek, func(r rune) bool { return unicode.Is(unicode.Greek, r) }},
+		// no any-char runes in the class: all of its runes are kept
+		{`[^\x00-\x{FF}]{20}`, DefaultAnyChar, func(r rune) bool { return r > 0xFF }},
+	}
+	for _, test := range tests {
+		g, err := NewWithAnyChar(randchars.NewGeneratorWithSeed(0), test.expr, DefaultMaxRepeat, test.anyChar)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.expr, err)
+		}
+		for i := 0; i < 200; i++ {
+			for _, r := range string(g.Generate()) {
+				if !test.in(r) {
+					t.Fatalf("%s: got %q; want it to be in the any-char runes", test.expr, r)
+				}
+			}
+		}
+	}
+	// nil uses all of Unicode
+	g, _ := NewWithAnyChar(randchars.NewGeneratorWithSeed(0), `.{20}`, DefaultMaxRepeat, nil)
+	var n int
+	for i := 0; i < 100; i++ {
+		for _, r := range string(g.Generate()) {
+			if r > unicode.MaxASCII {
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		t.Error("nil: got only ASCII; want all of Unicode")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	g, _ := New(randchars.NewGenerator(), `[0-9]{8}`)
+	dst := make([]byte, 0, 16)
+	dst = append(dst, "id:"...)
+	dst = g.Append(dst)
+	if !regexp.MustCompile(`^id:[0-9]{8}$`).Match(dst) {
+		t.Errorf("got %q; want id: followed by 8 digits", dst)
+	}
+}
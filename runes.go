@@ -0,0 +1,156 @@
+package randchars
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RuneCharset is a set of unique runes from which random characters are
+// generated. Unlike Charset, it is not limited to ASCII and may contain more
+// than 256 symbols, e.g. all of the runes in a Unicode script. The rejection
+// threshold needed to select a rune without bias is computed when the
+// RuneCharset is created.
+//
+// The zero value is an empty RuneCharset and cannot be used for generation.
+type RuneCharset struct {
+	runes       []rune
+	threshold32 uint32
+}
+
+// NewRuneCharset returns a RuneCharset made up of the runes in s. Duplicate
+// runes are removed; the first occurrence determines a rune's position. An
+// error is returned if s is empty or is not valid UTF-8.
+func NewRuneCharset(s string) (RuneCharset, error) {
+	seen := make(map[rune]bool)
+	var runes []rune
+	for i, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				return RuneCharset{}, fmt.Errorf("%#x: invalid UTF-8 at byte %d", s[i], i)
+			}
+		}
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		runes = append(runes, r)
+	}
+	return newRuneCharset(runes)
+}
+
+// NewRuneCharsetFromTables returns a RuneCharset made up of every rune in the
+// received tables, e.g. unicode.Greek or unicode.Cyrillic. The runes are in
+// ascending order. An error is returned if the tables contain no runes or a
+// rune that cannot be encoded as UTF-8, e.g. a surrogate.
+func NewRuneCharsetFromTables(tables ...*unicode.RangeTable) (RuneCharset, error) {
+	seen := make(map[rune]bool)
+	var runes []rune
+	add := func(lo, hi, stride uint32) error {
+		for r := lo; r <= hi; r += stride {
+			if !utf8.ValidRune(rune(r)) {
+				return fmt.Errorf("%U: not a valid rune", r)
+			}
+			if seen[rune(r)] {
+				continue
+			}
+			seen[rune(r)] = true
+			runes = append(runes, rune(r))
+		}
+		return nil
+	}
+	for _, t := range tables {
+		for _, r16 := range t.R16 {
+			err := add(uint32(r16.Lo), uint32(r16.Hi), uint32(r16.Stride))
+			if err != nil {
+				return RuneCharset{}, err
+			}
+		}
+		for _, r32 := range t.R32 {
+			err := add(r32.Lo, r32.Hi, r32.Stride)
+			if err != nil {
+				return RuneCharset{}, err
+			}
+		}
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return newRuneCharset(runes)
+}
+
+func newRuneCharset(runes []rune) (RuneCharset, error) {
+	if len(runes) == 0 {
+		return RuneCharset{}, ErrEmptyCharset
+	}
+	n := uint32(len(runes))
+	return RuneCharset{runes: runes, threshold32: -n % n}, nil
+}
+
+// MustRuneCharset is like NewRuneCharset but panics if s is not a valid
+// RuneCharset.
+func MustRuneCharset(s string) RuneCharset {
+	cs, err := NewRuneCharset(s)
+	if err != nil {
+		panic(err)
+	}
+	return cs
+}
+
+// Exclude returns a RuneCharset made up of the runes in cs that are not in
+// runes. The rejection threshold is computed for the new RuneCharset.
+// ErrEmptyCharset is returned if no runes remain.
+func (cs RuneCharset) Exclude(runes string) (RuneCharset, error) {
+	var r []rune
+	for _, v := range cs.runes {
+		if !strings.ContainsRune(runes, v) {
+			r = append(r, v)
+		}
+	}
+	return newRuneCharset(r)
+}
+
+// String returns the runes in the RuneCharset.
+func (cs RuneCharset) String() string {
+	return string(cs.runes)
+}
+
+// Len returns the number of runes in the RuneCharset.
+func (cs RuneCharset) Len() int {
+	return len(cs.runes)
+}
+
+// Entropy returns the entropy, in bits, of n runes generated from the
+// RuneCharset.
+func (cs RuneCharset) Entropy(n int) float64 {
+	return Entropy(cs.Len(), n)
+}
+
+// MinLength returns the minimum number of runes to generate from the
+// RuneCharset for at least bits of entropy; -1 is returned if no length
+// suffices, i.e. the RuneCharset has only one rune.
+func (cs RuneCharset) MinLength(bits float64) int {
+	return MinLength(cs.Len(), bits)
+}
+
+// Rune returns the i'th rune in the RuneCharset.
+func (cs RuneCharset) Rune(i int) rune {
+	return cs.runes[i]
+}
+
+// Contains reports whether r is in the RuneCharset.
+func (cs RuneCharset) Contains(r rune) bool {
+	for _, v := range cs.runes {
+		if v == r {
+			return true
+		}
+	}
+	return false
+}
+
+// Threshold32 returns the rejection threshold for selecting a rune using a
+// uniformly distributed uint32: values less than it must be discarded for
+// v % Len() to be unbiased.
+func (cs RuneCharset) Threshold32() uint32 {
+	return cs.threshold32
+}
@@ -0,0 +1,67 @@
+package randchars
+
+import "fmt"
+
+// IntNer is implemented by generators that return unbiased random ints in
+// [0, n). Both Generator and crandchars.Generator implement it; a seeded
+// Generator makes Choice, Sample, and Shuffle reproducible.
+type IntNer interface {
+	IntN(n int) int
+}
+
+// Choice returns a randomly chosen element of s. This will panic if s is
+// empty.
+func Choice[T any](r IntNer, s []T) T {
+	if len(s) == 0 {
+		panic(fmt.Sprintf("%d: value out of bounds", len(s)))
+	}
+	return s[r.IntN(len(s))]
+}
+
+// Shuffle randomly reorders the elements of s in place; every permutation is
+// equally likely.
+func Shuffle[T any](r IntNer, s []T) {
+	for i := len(s) - 1; i > 0; i-- {
+		j := r.IntN(i + 1)
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// Sample returns k elements of s chosen without replacement, in random
+// order; s isn't modified. Every k element sequence is equally likely. This
+// will panic if k < 0 or k > len(s).
+//
+// The elements are chosen by the first k steps of a Fisher-Yates shuffle.
+// When k is small relative to len(s), the swaps are tracked in a map instead
+// of a copy of s, so the cost depends on k instead of len(s); the result is
+// the same either way.
+func Sample[T any](r IntNer, s []T, k int) []T {
+	if k < 0 || k > len(s) {
+		panic(fmt.Sprintf("%d: value out of bounds", k))
+	}
+	if k > len(s)/4 {
+		c := make([]T, len(s))
+		copy(c, s)
+		for i := 0; i < k; i++ {
+			j := i + r.IntN(len(c)-i)
+			c[i], c[j] = c[j], c[i]
+		}
+		return c[:k:k]
+	}
+	// swapped maps an index of s to the index of the element that has been
+	// swapped into it.
+	swapped := make(map[int]int, k)
+	at := func(i int) int {
+		if v, ok := swapped[i]; ok {
+			return v
+		}
+		return i
+	}
+	dst := make([]T, k)
+	for i := range dst {
+		j := i + r.IntN(len(s)-i)
+		dst[i] = s[at(j)]
+		swapped[j] = at(i)
+	}
+	return dst
+}
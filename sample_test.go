@@ -0,0 +1,145 @@
+package randchars
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestChoice(t *testing.T) {
+	s := []string{"a", "b", "c"}
+	counts := make(map[string]int)
+	g := NewGeneratorWithSeed(0)
+	for i := 0; i < 3000; i++ {
+		counts[Choice(g, s)]++
+	}
+	for _, v := range s {
+		if counts[v] < 900 || counts[v] > 1100 {
+			t.Errorf("%q: chosen %d times; want about 1000", v, counts[v])
+		}
+	}
+	// seeding makes it reproducible
+	a, b := NewGeneratorWithSeed(42), NewGeneratorWithSeed(42)
+	for i := 0; i < 100; i++ {
+		if x, y := Choice(a, s), Choice(b, s); x != y {
+			t.Fatalf("%d: got %q and %q from the same seed", i, x, y)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic; got none")
+		}
+	}()
+	Choice(g, []int{})
+}
+
+func TestShuffle(t *testing.T) {
+	// a chi-square test of the 6 permutations of 3 elements; the critical
+	// value is for p = 0.001 with 5 degrees of freedom.
+	g := NewGeneratorWithSeed(0)
+	counts := make(map[string]int)
+	for i := 0; i < 60000; i++ {
+		s := []int{1, 2, 3}
+		Shuffle(g, s)
+		counts[fmt.Sprint(s)]++
+	}
+	if len(counts) != 6 {
+		t.Fatalf("got %d permutations; want 6", len(counts))
+	}
+	var chi float64
+	for _, n := range counts {
+		d := float64(n - 10000)
+		chi += d * d / 10000
+	}
+	if chi > 20.52 {
+		t.Errorf("chi-square %.2f exceeds 20.52", chi)
+	}
+	a, b := []int{1, 2, 3, 4, 5, 6, 7, 8}, []int{1, 2, 3, 4, 5, 6, 7, 8}
+	Shuffle(NewGeneratorWithSeed(42), a)
+	Shuffle(NewGeneratorWithSeed(42), b)
+	if !slices.Equal(a, b) {
+		t.Errorf("got %v and %v from the same seed", a, b)
+	}
+	Shuffle(g, []int{})
+}
+
+func TestSample(t *testing.T) {
+	s := make([]int, 100)
+	for i := range s {
+		s[i] = i
+	}
+	for _, k := range []int{0, 1, 10, 25, 26, 50, 100} {
+		// the sparse and the copying paths give the same result as a
+		// Fisher-Yates shuffle of a copy
+		g, ref := NewGeneratorWithSeed(int64(k)), NewGeneratorWithSeed(int64(k))
+		got := Sample(g, s, k)
+		c := slices.Clone(s)
+		for i := 0; i < k; i++ {
+			j := i + ref.IntN(len(c)-i)
+			c[i], c[j] = c[j], c[i]
+		}
+		if !slices.Equal(got, c[:k]) {
+			t.Errorf("%d: got %v; want %v", k, got, c[:k])
+		}
+		seen := make(map[int]bool)
+		for _, v := range got {
+			if seen[v] {
+				t.Errorf("%d: %d was sampled more than once", k, v)
+			}
+			seen[v] = true
+		}
+	}
+	for i := range s {
+		if s[i] != i {
+			t.Fatalf("s was modified: %v", s)
+		}
+	}
+	// a chi-square test of the 56 ordered samples of 2 from 8 using the
+	// sparse path; the critical value is for p = 0.001 with 55 degrees of
+	// freedom.
+	g := NewGeneratorWithSeed(0)
+	counts := make(map[string]int)
+	for i := 0; i < 120000; i++ {
+		counts[fmt.Sprint(Sample(g, s[:8], 2))]++
+	}
+	if len(counts) != 56 {
+		t.Fatalf("got %d samples; want 56", len(counts))
+	}
+	var chi float64
+	for _, n := range counts {
+		d := float64(n) - 120000.0/56
+		chi += d * d / (120000.0 / 56)
+	}
+	if chi > 93.17 {
+		t.Errorf("chi-square %.2f exceeds 93.17", chi)
+	}
+	for _, k := range []int{-1, 101} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d: expected a panic; got none", k)
+				}
+			}()
+			Sample(g, s, k)
+		}()
+	}
+}
+
+func BenchmarkSample_10of1000(b *testing.B) {
+	g := NewGenerator()
+	s := make([]int, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Sample(g, s, 10)
+	}
+}
+
+func BenchmarkShuffle_1000(b *testing.B) {
+	g := NewGenerator()
+	s := make([]int, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Shuffle(g, s)
+	}
+}
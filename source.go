@@ -0,0 +1,293 @@
+package randchars
+
+import (
+	"math/bits"
+	"math/rand/v2"
+
+	pcg "github.com/dgryski/go-pcgr"
+	xoro "github.com/dgryski/go-xoroshiro"
+)
+
+// Source is a source of uniformly distributed random values. A Generator can
+// use any Source; the Source determines the speed, the quality, and the
+// period of the generated values.
+//
+// A Source is not safe for concurrent use.
+type Source interface {
+	// Seed seeds the Source; the same seed results in the same sequence of
+	// values.
+	Seed(seed int64)
+	// Uint32 returns a random uint32.
+	Uint32() uint32
+	// Uint64 returns a random uint64.
+	Uint64() uint64
+	// Uint32N returns an unbiased random uint32 in [0, n). n must be > 0.
+	Uint32N(n uint32) uint32
+	// Uint64N returns an unbiased random uint64 in [0, n). n must be > 0.
+	Uint64N(n uint64) uint64
+}
+
+// uint32n returns an unbiased random uint32 in [0, n) using next.
+func uint32n(next func() uint32, n uint32) uint32 {
+	threshold := -n % n
+	for {
+		r := next()
+		if r >= threshold {
+			return r % n
+		}
+	}
+}
+
+// uint64n returns an unbiased random uint64 in [0, n) using next.
+func uint64n(next func() uint64, n uint64) uint64 {
+	threshold := -n % n
+	for {
+		r := next()
+		if r >= threshold {
+			return r % n
+		}
+	}
+}
+
+// splitmix64 returns the next value of the SplitMix64 sequence in state; it
+// is used to expand a seed into a Source's state.
+func splitmix64(state *uint64) uint64 {
+	*state += 0x9e3779b97f4a7c15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// PCGSource is a Source that implements PCG, www.pcg-random.org, using
+// go-pcgr by Damian Gryski. It is the Source used by NewGenerator. Each value
+// is 32 bits, so a Uint64 uses two.
+type PCGSource struct {
+	rng pcg.Rand
+}
+
+// NewPCGSource returns a PCGSource using the received values as its seed and
+// state; the state selects one of 2^63 streams.
+func NewPCGSource(seed, state int64) *PCGSource {
+	return &PCGSource{pcg.New(seed, state)}
+}
+
+// Seed seeds the PCGSource using the first stream.
+func (s *PCGSource) Seed(seed int64) {
+	s.rng.Seed(seed)
+}
+
+// SeedWithState seeds the PCGSource and selects its stream.
+func (s *PCGSource) SeedWithState(seed, state int64) {
+	s.rng.SeedWithState(seed, state)
+}
+
+// Uint32 returns a random uint32.
+func (s *PCGSource) Uint32() uint32 {
+	return s.rng.Next()
+}
+
+// Uint64 returns a random uint64.
+func (s *PCGSource) Uint64() uint64 {
+	return uint64(s.rng.Next())<<32 | uint64(s.rng.Next())
+}
+
+// Uint32N returns an unbiased random uint32 in [0, n).
+func (s *PCGSource) Uint32N(n uint32) uint32 {
+	return s.rng.Bound(n)
+}
+
+// Uint64N returns an unbiased random uint64 in [0, n).
+func (s *PCGSource) Uint64N(n uint64) uint64 {
+	return uint64n(s.Uint64, n)
+}
+
+// XoroshiroSource is a Source that implements XORoShiRo128+,
+// http://xoroshiro.di.unimi.it/, using go-xoroshiro by Damian Gryski. It is
+// the Source used by NewBase64Generator and NewBase64URLGenerator. It is
+// fast but its lowest bits are weak, so Uint32 uses the high bits.
+type XoroshiroSource struct {
+	rng xoro.State
+}
+
+// NewXoroshiroSource returns a XoroshiroSource using the received value as
+// its seed.
+func NewXoroshiroSource(seed int64) *XoroshiroSource {
+	return &XoroshiroSource{xoro.New(seed)}
+}
+
+// Seed seeds the XoroshiroSource.
+func (s *XoroshiroSource) Seed(seed int64) {
+	s.rng.Seed(seed)
+}
+
+// Uint32 returns a random uint32.
+func (s *XoroshiroSource) Uint32() uint32 {
+	return uint32(s.rng.Uint64() >> 32)
+}
+
+// Uint64 returns a random uint64.
+func (s *XoroshiroSource) Uint64() uint64 {
+	return s.rng.Uint64()
+}
+
+// Uint32N returns an unbiased random uint32 in [0, n).
+func (s *XoroshiroSource) Uint32N(n uint32) uint32 {
+	return uint32n(s.Uint32, n)
+}
+
+// Uint64N returns an unbiased random uint64 in [0, n).
+func (s *XoroshiroSource) Uint64N(n uint64) uint64 {
+	return uint64n(s.Uint64, n)
+}
+
+// Xoshiro256Source is a Source that implements xoshiro256**,
+// https://prng.di.unimi.it/, a general purpose PRNG with a period of
+// 2^256 - 1 that doesn't have XORoShiRo128+'s weak low bits.
+type Xoshiro256Source struct {
+	s [4]uint64
+}
+
+// NewXoshiro256Source returns a Xoshiro256Source using the received value as
+// its seed.
+func NewXoshiro256Source(seed int64) *Xoshiro256Source {
+	var s Xoshiro256Source
+	s.Seed(seed)
+	return &s
+}
+
+// Seed seeds the Xoshiro256Source; the seed is expanded into its state using
+// SplitMix64.
+func (s *Xoshiro256Source) Seed(seed int64) {
+	v := uint64(seed)
+	for i := range s.s {
+		s.s[i] = splitmix64(&v)
+	}
+}
+
+// Uint32 returns a random uint32.
+func (s *Xoshiro256Source) Uint32() uint32 {
+	return uint32(s.Uint64() >> 32)
+}
+
+// Uint64 returns a random uint64.
+func (s *Xoshiro256Source) Uint64() uint64 {
+	result := bits.RotateLeft64(s.s[1]*5, 7) * 9
+	t := s.s[1] << 17
+	s.s[2] ^= s.s[0]
+	s.s[3] ^= s.s[1]
+	s.s[1] ^= s.s[2]
+	s.s[0] ^= s.s[3]
+	s.s[2] ^= t
+	s.s[3] = bits.RotateLeft64(s.s[3], 45)
+	return result
+}
+
+// Uint32N returns an unbiased random uint32 in [0, n).
+func (s *Xoshiro256Source) Uint32N(n uint32) uint32 {
+	return uint32n(s.Uint32, n)
+}
+
+// Uint64N returns an unbiased random uint64 in [0, n).
+func (s *Xoshiro256Source) Uint64N(n uint64) uint64 {
+	return uint64n(s.Uint64, n)
+}
+
+// WyRandSource is a Source that implements wyrand, from Wang Yi's wyhash,
+// https://github.com/wangyi-fudan/wyhash. It is fast and has a single 64 bit
+// word of state, which limits its period to 2^64.
+type WyRandSource struct {
+	state uint64
+}
+
+// NewWyRandSource returns a WyRandSource using the received value as its
+// seed.
+func NewWyRandSource(seed int64) *WyRandSource {
+	return &WyRandSource{uint64(seed)}
+}
+
+// Seed seeds the WyRandSource.
+func (s *WyRandSource) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// Uint32 returns a random uint32.
+func (s *WyRandSource) Uint32() uint32 {
+	return uint32(s.Uint64() >> 32)
+}
+
+// Uint64 returns a random uint64.
+func (s *WyRandSource) Uint64() uint64 {
+	s.state += 0xa0761d6478bd642f
+	hi, lo := bits.Mul64(s.state, s.state^0xe7037ed1a0b428db)
+	return hi ^ lo
+}
+
+// Uint32N returns an unbiased random uint32 in [0, n).
+func (s *WyRandSource) Uint32N(n uint32) uint32 {
+	return uint32n(s.Uint32, n)
+}
+
+// Uint64N returns an unbiased random uint64 in [0, n).
+func (s *WyRandSource) Uint64N(n uint64) uint64 {
+	return uint64n(s.Uint64, n)
+}
+
+// ChaCha8Source is a Source that uses math/rand/v2's ChaCha8, a
+// cryptographically strong PRNG. It is the slowest of the Sources but its
+// output can't be predicted without knowing its seed.
+type ChaCha8Source struct {
+	rng *rand.ChaCha8
+}
+
+// NewChaCha8Source returns a ChaCha8Source using the received value as its
+// seed.
+func NewChaCha8Source(seed int64) *ChaCha8Source {
+	return &ChaCha8Source{rand.NewChaCha8(chacha8Seed(seed))}
+}
+
+// NewChaCha8SourceWithKey returns a ChaCha8Source using the received 32 byte
+// key as its seed. Unlike the other Sources, the full key can be used, which
+// is needed for its output to be unpredictable.
+func NewChaCha8SourceWithKey(key [32]byte) *ChaCha8Source {
+	return &ChaCha8Source{rand.NewChaCha8(key)}
+}
+
+// chacha8Seed expands seed into a ChaCha8 key using SplitMix64.
+func chacha8Seed(seed int64) [32]byte {
+	var key [32]byte
+	v := uint64(seed)
+	for i := 0; i < len(key); i += 8 {
+		r := splitmix64(&v)
+		for j := 0; j < 8; j++ {
+			key[i+j] = byte(r >> (8 * j))
+		}
+	}
+	return key
+}
+
+// Seed seeds the ChaCha8Source; the seed is expanded into a 32 byte key
+// using SplitMix64.
+func (s *ChaCha8Source) Seed(seed int64) {
+	s.rng.Seed(chacha8Seed(seed))
+}
+
+// Uint32 returns a random uint32.
+func (s *ChaCha8Source) Uint32() uint32 {
+	return uint32(s.rng.Uint64() >> 32)
+}
+
+// Uint64 returns a random uint64.
+func (s *ChaCha8Source) Uint64() uint64 {
+	return s.rng.Uint64()
+}
+
+// Uint32N returns an unbiased random uint32 in [0, n).
+func (s *ChaCha8Source) Uint32N(n uint32) uint32 {
+	return uint32n(s.Uint32, n)
+}
+
+// Uint64N returns an unbiased random uint64 in [0, n).
+func (s *ChaCha8Source) Uint64N(n uint64) uint64 {
+	return uint64n(s.Uint64, n)
+}
@@ -0,0 +1,141 @@
+package randchars
+
+import (
+	"testing"
+)
+
+// sources returns a seeded instance of each Source.
+func sources(seed int64) []struct {
+	name string
+	src  Source
+} {
+	return []struct {
+		name string
+		src  Source
+	}{
+		{"PCG", NewPCGSource(seed, 0)},
+		{"Xoroshiro", NewXoroshiroSource(seed)},
+		{"Xoshiro256", NewXoshiro256Source(seed)},
+		{"WyRand", NewWyRandSource(seed)},
+		{"ChaCha8", NewChaCha8Source(seed)},
+	}
+}
+
+func TestXoshiro256(t *testing.T) {
+	// the first values from the reference implementation's state of 1, 2, 3, 4
+	s := &Xoshiro256Source{[4]uint64{1, 2, 3, 4}}
+	for i, v := range []uint64{11520, 0, 1509978240, 1215971899390074240} {
+		if r := s.Uint64(); r != v {
+			t.Errorf("%d: got %d; want %d", i, r, v)
+		}
+	}
+}
+
+func TestSourceSeed(t *testing.T) {
+	for _, test := range sources(42) {
+		var first [8]uint64
+		for i := range first {
+			first[i] = test.src.Uint64()
+		}
+		test.src.Seed(42)
+		for i, v := range first {
+			if r := test.src.Uint64(); r != v {
+				t.Errorf("%s: %d: got %d after seeding; want %d", test.name, i, r, v)
+			}
+		}
+		test.src.Seed(43)
+		if test.src.Uint64() == first[0] {
+			t.Errorf("%s: a different seed resulted in the same value", test.name)
+		}
+	}
+}
+
+func TestSourceBounded(t *testing.T) {
+	for _, test := range sources(0) {
+		for _, n := range []uint32{1, 2, 3, 10, 62, 1<<31 + 1, 1<<32 - 1} {
+			for i := 0; i < 100; i++ {
+				if v := test.src.Uint32N(n); v >= n {
+					t.Fatalf("%s: Uint32N(%d): got %d", test.name, n, v)
+				}
+			}
+		}
+		for _, n := range []uint64{1, 3, 1<<32 + 1, 1<<63 + 1, 1<<64 - 1} {
+			for i := 0; i < 100; i++ {
+				if v := test.src.Uint64N(n); v >= n {
+					t.Fatalf("%s: Uint64N(%d): got %d", test.name, n, v)
+				}
+			}
+		}
+	}
+}
+
+func TestSourceUniform(t *testing.T) {
+	// a chi-square test of each Source through the Generator; see
+	// TestCharsUniform.
+	for _, test := range sources(0) {
+		g := NewGeneratorWithSource(test.src)
+		for _, cs := range []Charset{AlphaNumCharset, Base64Charset} {
+			b := g.Chars(cs, cs.Len()*10000)
+			counts := make(map[byte]int)
+			for _, c := range b {
+				counts[c]++
+			}
+			var chi float64
+			for i := 0; i < cs.Len(); i++ {
+				d := float64(counts[cs.chars[i]] - 10000)
+				chi += d * d / 10000
+			}
+			critical := 100.88
+			if cs.Len() == 64 {
+				critical = 103.44
+			}
+			if chi > critical {
+				t.Errorf("%s: %s: chi-square %.2f exceeds %.2f", test.name, cs, chi, critical)
+			}
+		}
+	}
+}
+
+func TestGeneratorWithSource(t *testing.T) {
+	// the default Generator is a PCGSource
+	g1 := NewGeneratorSeedWithState(7, 3)
+	g2 := NewGeneratorWithSource(NewPCGSource(7, 3))
+	if a, b := g1.AlphaNum(32), g2.AlphaNum(32); string(a) != string(b) {
+		t.Errorf("got %q and %q; want the same output", a, b)
+	}
+	// SeedWithState falls back to Seed for Sources without a state
+	g := NewGeneratorWithSource(NewWyRandSource(0))
+	g.SeedWithState(5, 9)
+	a := g.AlphaNum(32)
+	g.Seed(5)
+	if b := g.AlphaNum(32); string(a) != string(b) {
+		t.Errorf("got %q and %q; want the same output", a, b)
+	}
+	// the Base64 generators use a XoroshiroSource by default
+	b1 := NewBase64GeneratorWithSeed(11)
+	b2 := NewBase64GeneratorWithSource(NewXoroshiroSource(11))
+	if a, b := b1.Bytes(32), b2.Bytes(32); string(a) != string(b) {
+		t.Errorf("got %q and %q; want the same output", a, b)
+	}
+	for _, c := range NewBase64GeneratorWithSource(NewChaCha8Source(0)).Bytes(1000) {
+		if !Base64Charset.Contains(c) {
+			t.Fatalf("%q: not a Base64 character", c)
+		}
+	}
+}
+
+func benchmarkSource(b *testing.B, src Source) {
+	g := NewGeneratorWithSource(src)
+	dst := make([]byte, 32)
+	b.SetBytes(int64(len(dst)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.FillAlphaNum(dst)
+	}
+}
+
+func BenchmarkSourcePCG_32(b *testing.B)        { benchmarkSource(b, NewPCGSource(0, 0)) }
+func BenchmarkSourceXoroshiro_32(b *testing.B)  { benchmarkSource(b, NewXoroshiroSource(0)) }
+func BenchmarkSourceXoshiro256_32(b *testing.B) { benchmarkSource(b, NewXoshiro256Source(0)) }
+func BenchmarkSourceWyRand_32(b *testing.B)     { benchmarkSource(b, NewWyRandSource(0)) }
+func BenchmarkSourceChaCha8_32(b *testing.B)    { benchmarkSource(b, NewChaCha8Source(0)) }
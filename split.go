@@ -0,0 +1,209 @@
+package randchars
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCannotSplit is returned when a generator's Source doesn't support the
+// requested operation: Split for a Source that isn't a Splitter and Jump for
+// one that isn't a Jumper.
+var ErrCannotSplit = errors.New("randchars: Source cannot be split or jumped")
+
+// Jumper is implemented by Sources that can jump ahead: advance their state
+// as if a large, fixed, number of values had been generated, without
+// generating them.
+type Jumper interface {
+	Jump()
+}
+
+// Splitter is implemented by Sources that can be split: return a new Source
+// whose values don't overlap with those of the Source.
+type Splitter interface {
+	Split() Source
+}
+
+// Split returns a new PCGSource that uses a different stream. The stream and
+// seed are chosen using values from s so, unlike streams selected with
+// NewPCGSource, two splits only select the same stream with a probability of
+// 2^-63.
+func (s *PCGSource) Split() Source {
+	seed := int64(s.Uint64())
+	stream := int64(s.Uint64() >> 1)
+	if uint64(stream<<1)|1 == s.rng.Inc {
+		stream ^= 1
+	}
+	return NewPCGSource(seed, stream)
+}
+
+// Jump advances the XoroshiroSource by 2^64 values; 2^64 non-overlapping
+// sequences of 2^64 values can be generated by jumping.
+func (s *XoroshiroSource) Jump() {
+	s.rng.Jump()
+}
+
+// Split returns a copy of the XoroshiroSource and then jumps the
+// XoroshiroSource ahead, so the copy generates the next 2^64 values and the
+// XoroshiroSource continues after them.
+func (s *XoroshiroSource) Split() Source {
+	c := *s
+	s.Jump()
+	return &c
+}
+
+// xoshiro256Jump is the jump polynomial for xoshiro256**.
+var xoshiro256Jump = [4]uint64{0x180ec6d33cfd0aba, 0xd5a61266f0c9392c, 0xa9582618e03fc9aa, 0x39abdc4529b1661c}
+
+// Jump advances the Xoshiro256Source by 2^128 values; 2^128 non-overlapping
+// sequences of 2^128 values can be generated by jumping.
+func (s *Xoshiro256Source) Jump() {
+	var t [4]uint64
+	for _, j := range xoshiro256Jump {
+		for b := 0; b < 64; b++ {
+			if j&(1<<b) != 0 {
+				t[0] ^= s.s[0]
+				t[1] ^= s.s[1]
+				t[2] ^= s.s[2]
+				t[3] ^= s.s[3]
+			}
+			s.Uint64()
+		}
+	}
+	s.s = t
+}
+
+// Split returns a copy of the Xoshiro256Source and then jumps the
+// Xoshiro256Source ahead, so the copy generates the next 2^128 values and the
+// Xoshiro256Source continues after them.
+func (s *Xoshiro256Source) Split() Source {
+	c := *s
+	s.Jump()
+	return &c
+}
+
+// Split returns a new ChaCha8Source whose key is generated by the
+// ChaCha8Source; the new Source's values can't be predicted from the
+// ChaCha8Source's, or vice versa.
+func (s *ChaCha8Source) Split() Source {
+	var key [32]byte
+	for i := 0; i < len(key); i += 8 {
+		r := s.Uint64()
+		for j := 0; j < 8; j++ {
+			key[i+j] = byte(r >> (8 * j))
+		}
+	}
+	return NewChaCha8SourceWithKey(key)
+}
+
+// split returns a Source split from src.
+func split(src Source) (Source, error) {
+	s, ok := src.(Splitter)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T is not a Splitter", ErrCannotSplit, src)
+	}
+	return s.Split(), nil
+}
+
+// jump jumps src ahead.
+func jump(src Source) error {
+	j, ok := src.(Jumper)
+	if !ok {
+		return fmt.Errorf("%w: %T is not a Jumper", ErrCannotSplit, src)
+	}
+	j.Jump()
+	return nil
+}
+
+// Split returns a new Generator whose values don't overlap with g's; see the
+// Split method of g's Source. An error wrapping ErrCannotSplit is returned if
+// g's Source isn't a Splitter.
+func (g *Generator) Split() (*Generator, error) {
+	src, err := split(g.src)
+	if err != nil {
+		return nil, err
+	}
+	return &Generator{src}, nil
+}
+
+// Jump jumps g's Source ahead; see the Jump method of g's Source. An error
+// wrapping ErrCannotSplit is returned if g's Source isn't a Jumper, e.g. the
+// default PCGSource, whose streams are used instead.
+func (g *Generator) Jump() error {
+	return jump(g.src)
+}
+
+// Split returns a new Base64Generator whose values don't overlap with g's;
+// see Generator.Split.
+func (g *Base64Generator) Split() (*Base64Generator, error) {
+	src, err := split(g.src)
+	if err != nil {
+		return nil, err
+	}
+	return &Base64Generator{src}, nil
+}
+
+// Jump jumps g's Source ahead; see Generator.Jump.
+func (g *Base64Generator) Jump() error {
+	return jump(g.src)
+}
+
+// Split returns a new Base64URLGenerator whose values don't overlap with g's;
+// see Generator.Split.
+func (g *Base64URLGenerator) Split() (*Base64URLGenerator, error) {
+	src, err := split(g.src)
+	if err != nil {
+		return nil, err
+	}
+	return &Base64URLGenerator{src}, nil
+}
+
+// Jump jumps g's Source ahead; see Generator.Jump.
+func (g *Base64URLGenerator) Jump() error {
+	return jump(g.src)
+}
+
+// NewGenerators returns n Generators, derived from seed, that generate
+// independent, reproducible, values: each is split, see PCGSource.Split, from
+// a PCGSource seeded with seed, so each has its own seed and stream. This will
+// panic if n < 0.
+func NewGenerators(seed int64, n int) []*Generator {
+	if n < 0 {
+		panic(fmt.Sprintf("%d: value out of bounds", n))
+	}
+	gens := make([]*Generator, n)
+	src := NewPCGSource(seed, 0)
+	for i := range gens {
+		gens[i] = &Generator{src.Split()}
+	}
+	return gens
+}
+
+// NewBase64Generators returns n Base64Generators, derived from seed, that
+// generate non-overlapping, reproducible, values: each is the previous one
+// jumped ahead by 2^64 values. This will panic if n < 0.
+func NewBase64Generators(seed int64, n int) []*Base64Generator {
+	if n < 0 {
+		panic(fmt.Sprintf("%d: value out of bounds", n))
+	}
+	gens := make([]*Base64Generator, n)
+	src := NewXoroshiroSource(seed)
+	for i := range gens {
+		gens[i] = &Base64Generator{src.Split()}
+	}
+	return gens
+}
+
+// NewBase64URLGenerators returns n Base64URLGenerators, derived from seed,
+// that generate non-overlapping, reproducible, values; see
+// NewBase64Generators. This will panic if n < 0.
+func NewBase64URLGenerators(seed int64, n int) []*Base64URLGenerator {
+	if n < 0 {
+		panic(fmt.Sprintf("%d: value out of bounds", n))
+	}
+	gens := make([]*Base64URLGenerator, n)
+	src := NewXoroshiroSource(seed)
+	for i := range gens {
+		gens[i] = &Base64URLGenerator{src.Split()}
+	}
+	return gens
+}
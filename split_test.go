@@ -0,0 +1,132 @@
+package randchars
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJump(t *testing.T) {
+	// a jump is a power of the transition function so it commutes with
+	// generating a value.
+	for _, test := range []struct {
+		name string
+		a, b interface {
+			Source
+			Jumper
+		}
+	}{
+		{"Xoroshiro", NewXoroshiroSource(1), NewXoroshiroSource(1)},
+		{"Xoshiro256", NewXoshiro256Source(1), NewXoshiro256Source(1)},
+	} {
+		test.a.Jump()
+		test.a.Uint64()
+		test.b.Uint64()
+		test.b.Jump()
+		for i := 0; i < 10; i++ {
+			if a, b := test.a.Uint64(), test.b.Uint64(); a != b {
+				t.Fatalf("%s: %d: got %d and %d; want the same value", test.name, i, a, b)
+			}
+		}
+	}
+	// a jump changes the state
+	c := NewXoshiro256Source(1)
+	d := *c
+	d.Jump()
+	if c.Uint64() == d.Uint64() {
+		t.Error("jumping didn't change the values")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	for _, test := range sources(3) {
+		if _, ok := test.src.(Splitter); !ok {
+			continue
+		}
+		g := NewGeneratorWithSource(test.src)
+		children := make(map[string]bool)
+		parent := string(g.AlphaNum(64))
+		for i := 0; i < 8; i++ {
+			c, err := g.Split()
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %s", test.name, err)
+			}
+			s := string(c.AlphaNum(64))
+			if s == parent || children[s] {
+				t.Fatalf("%s: split %d repeated values", test.name, i)
+			}
+			children[s] = true
+		}
+	}
+	// a split xoroshiro generator continues where the original was
+	g := NewBase64GeneratorWithSeed(9)
+	expected := string(NewBase64GeneratorWithSeed(9).Bytes(100))
+	c, err := g.Split()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := string(c.Bytes(100)); s != expected {
+		t.Errorf("got %q; want %q", s, expected)
+	}
+	if s := string(g.Bytes(100)); s == expected {
+		t.Error("the original generator wasn't jumped")
+	}
+}
+
+func TestSplitUnsupported(t *testing.T) {
+	g := NewGeneratorWithSource(NewWyRandSource(0))
+	if _, err := g.Split(); !errors.Is(err, ErrCannotSplit) {
+		t.Errorf("Split: got %v; want %v", err, ErrCannotSplit)
+	}
+	if err := g.Jump(); !errors.Is(err, ErrCannotSplit) {
+		t.Errorf("Jump: got %v; want %v", err, ErrCannotSplit)
+	}
+	if err := NewGenerator().Jump(); !errors.Is(err, ErrCannotSplit) {
+		t.Errorf("PCG Jump: got %v; want %v", err, ErrCannotSplit)
+	}
+	if err := NewBase64URLGenerator().Jump(); err != nil {
+		t.Errorf("Base64URL Jump: unexpected error: %s", err)
+	}
+}
+
+func TestNewGenerators(t *testing.T) {
+	gens := NewGenerators(42, 16)
+	again := NewGenerators(42, 16)
+	seen := make(map[string]bool)
+	for i, g := range gens {
+		s := string(g.AlphaNum(32))
+		if seen[s] {
+			t.Fatalf("%d: repeated another generator's values", i)
+		}
+		seen[s] = true
+		if s2 := string(again[i].AlphaNum(32)); s2 != s {
+			t.Errorf("%d: got %q and %q from the same seed", i, s, s2)
+		}
+	}
+	// each generator has its own seed and stream, not the same seed on
+	// streams 0 to n-1
+	streams := make(map[uint64]bool)
+	for i, g := range NewGenerators(42, 16) {
+		inc := g.src.(*PCGSource).rng.Inc
+		if streams[inc] {
+			t.Errorf("%d: repeated another generator's stream", i)
+		}
+		streams[inc] = true
+		if s, s2 := string(g.AlphaNum(32)), string(NewGeneratorSeedWithState(42, int64(i)).AlphaNum(32)); s == s2 {
+			t.Errorf("%d: got the values of seed 42 on stream %d", i, i)
+		}
+	}
+	b64 := NewBase64Generators(42, 4)
+	b64URL := NewBase64URLGenerators(42, 4)
+	seen = make(map[string]bool)
+	for i := range b64 {
+		s := string(b64[i].Bytes(32))
+		if seen[s] {
+			t.Fatalf("%d: repeated another generator's values", i)
+		}
+		seen[s] = true
+		b64URL[i].Bytes(32)
+	}
+	if len(NewGenerators(0, 0)) != 0 {
+		t.Error("got generators; want none")
+	}
+}
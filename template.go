@@ -0,0 +1,202 @@
+package randchars
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// MaxTemplateRepeat is the maximum count of a {cN} repetition in a
+// Template.
+const MaxTemplateRepeat = 1000
+
+// placeholders maps the placeholder characters that can be used in a
+// Template to their Charsets.
+var placeholders = map[byte]Charset{
+	'A': UpperAlphaCharset,
+	'a': LowerAlphaCharset,
+	'L': AlphaCharset,
+	'9': DigitsCharset,
+	'x': AlphaNumCharset,
+	'X': UpperAlphaNumCharset,
+}
+
+// Template generates characters with a fixed structure, e.g. license codes
+// or SKUs like "AAA-999-aaa". Each placeholder character in the template is
+// replaced by a random character from its Charset:
+//
+//	A  A-Z
+//	a  a-z
+//	L  a-zA-Z
+//	9  0-9
+//	x  a-zA-Z0-9
+//	X  A-Z0-9
+//
+// A placeholder can be repeated using {cN}, e.g. {A4} is the same as AAAA; N
+// can be at most MaxTemplateRepeat. Any other character is a literal. A
+// backslash escapes the following character, e.g. \A is a literal A and \{ is
+// a literal {.
+//
+// A Template is compiled once and can then be used to generate characters
+// any number of times, by any generator. It is safe for concurrent use.
+type Template struct {
+	tmpl     string
+	segments []segment
+	n        int
+}
+
+// segment is either a literal or n characters from cs.
+type segment struct {
+	literal string
+	cs      Charset
+	n       int
+}
+
+// CompileTemplate parses tmpl and returns a Template that can be used to
+// generate characters. An error is returned if tmpl is not a valid template.
+func CompileTemplate(tmpl string) (*Template, error) {
+	t := &Template{tmpl: tmpl}
+	var lit []byte
+	for i := 0; i < len(tmpl); i++ {
+		c := tmpl[i]
+		switch c {
+		case '\\':
+			i++
+			if i == len(tmpl) {
+				return nil, fmt.Errorf("template %q: trailing backslash", tmpl)
+			}
+			lit = append(lit, tmpl[i])
+		case '{':
+			end := i + 1
+			for end < len(tmpl) && tmpl[end] != '}' {
+				end++
+			}
+			if end == len(tmpl) {
+				return nil, fmt.Errorf("template %q: unterminated repetition at %d", tmpl, i)
+			}
+			rep := tmpl[i+1 : end]
+			if len(rep) < 2 {
+				return nil, fmt.Errorf("template %q: %q: invalid repetition", tmpl, tmpl[i:end+1])
+			}
+			cs, ok := placeholders[rep[0]]
+			if !ok {
+				return nil, fmt.Errorf("template %q: %q: %q is not a placeholder", tmpl, tmpl[i:end+1], rep[0])
+			}
+			n, err := strconv.Atoi(rep[1:])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("template %q: %q: invalid repetition count", tmpl, tmpl[i:end+1])
+			}
+			if n > MaxTemplateRepeat {
+				return nil, fmt.Errorf("template %q: %q: repetition count exceeds %d", tmpl, tmpl[i:end+1], MaxTemplateRepeat)
+			}
+			t.addLiteral(&lit)
+			t.addChars(cs, n)
+			i = end
+		default:
+			cs, ok := placeholders[c]
+			if !ok {
+				lit = append(lit, c)
+				continue
+			}
+			t.addLiteral(&lit)
+			t.addChars(cs, 1)
+		}
+	}
+	t.addLiteral(&lit)
+	return t, nil
+}
+
+// MustCompileTemplate is like CompileTemplate but panics if tmpl is not a
+// valid template.
+func MustCompileTemplate(tmpl string) *Template {
+	t, err := CompileTemplate(tmpl)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// addLiteral adds the pending literal, if any, as a segment.
+func (t *Template) addLiteral(lit *[]byte) {
+	if len(*lit) == 0 {
+		return
+	}
+	t.segments = append(t.segments, segment{literal: string(*lit)})
+	t.n += len(*lit)
+	*lit = (*lit)[:0]
+}
+
+// addChars adds n characters from cs; consecutive placeholders for the same
+// Charset are merged so they can be filled at once.
+func (t *Template) addChars(cs Charset, n int) {
+	t.n += n
+	if l := len(t.segments); l > 0 && t.segments[l-1].literal == "" && t.segments[l-1].cs == cs {
+		t.segments[l-1].n += n
+		return
+	}
+	t.segments = append(t.segments, segment{cs: cs, n: n})
+}
+
+// String returns the template the Template was compiled from.
+func (t *Template) String() string {
+	return t.tmpl
+}
+
+// Len returns the number of characters the Template generates.
+func (t *Template) Len() int {
+	return t.n
+}
+
+// Entropy returns the entropy, in bits, of the characters generated from the
+// Template; literals don't add any.
+func (t *Template) Entropy() float64 {
+	var e float64
+	for _, s := range t.segments {
+		if s.literal == "" {
+			e += s.cs.Entropy(s.n)
+		}
+	}
+	return e
+}
+
+// Generate returns the characters generated from the Template using g, e.g.
+// a Generator or a crandchars.Generator.
+func (t *Template) Generate(g CharsFiller) []byte {
+	return t.Append(make([]byte, 0, t.n), g)
+}
+
+// GenerateErr returns the characters generated from the Template using g.
+// Unlike Generate, if g is a CharsReader, e.g. a crandchars.Generator, its
+// error, e.g. one wrapping ErrEntropy if the CSPRNG fails, is returned
+// instead of panicking.
+func (t *Template) GenerateErr(g CharsFiller) ([]byte, error) {
+	dst := make([]byte, 0, t.n)
+	for _, s := range t.segments {
+		if s.literal != "" {
+			dst = append(dst, s.literal...)
+			continue
+		}
+		l := len(dst)
+		dst = dst[:l+s.n]
+		if err := readChars(g, dst[l:], s.cs); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// Append appends the characters generated from the Template using g to dst
+// and returns the extended slice. If dst has sufficient capacity, no
+// allocation is done.
+func (t *Template) Append(dst []byte, g CharsFiller) []byte {
+	for _, s := range t.segments {
+		if s.literal != "" {
+			dst = append(dst, s.literal...)
+			continue
+		}
+		l := len(dst)
+		dst = slices.Grow(dst, s.n)[:l+s.n]
+		g.FillChars(dst[l:], s.cs)
+	}
+	return dst
+}
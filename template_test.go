@@ -0,0 +1,123 @@
+package randchars
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileTemplate(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		n    int
+		err  string
+	}{
+		{"", 0, ""},
+		{"AAA-999-aaa", 11, ""},
+		{"{A4}-{94}", 9, ""},
+		{`SKU\-\A\\{x12}`, 18, ""},
+		{"SKU-9x", 6, ""},
+		{"{x0}", 0, ""},
+		{`abc\`, 0, `template "abc\\": trailing backslash`},
+		{"{A4", 0, `template "{A4": unterminated repetition at 0`},
+		{"{A}", 0, `template "{A}": "{A}": invalid repetition`},
+		{"{Z4}", 0, `template "{Z4}": "{Z4}": 'Z' is not a placeholder`},
+		{"{A-1}", 0, `template "{A-1}": "{A-1}": invalid repetition count`},
+		{"{Ax}", 0, `template "{Ax}": "{Ax}": invalid repetition count`},
+		{"{x1000}", 1000, ""},
+		{"{x1001}", 0, `template "{x1001}": "{x1001}": repetition count exceeds 1000`},
+		{"{x999999999}", 0, `template "{x999999999}": "{x999999999}": repetition count exceeds 1000`},
+	}
+	for _, test := range tests {
+		tmpl, err := CompileTemplate(test.tmpl)
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%q: got %q; want %q", test.tmpl, err, test.err)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%q: got no error; want %q", test.tmpl, test.err)
+			continue
+		}
+		if tmpl.Len() != test.n {
+			t.Errorf("%q: got len %d; want %d", test.tmpl, tmpl.Len(), test.n)
+		}
+		if tmpl.String() != test.tmpl {
+			t.Errorf("got %q; want %q", tmpl.String(), test.tmpl)
+		}
+	}
+}
+
+func TestTemplateGenerate(t *testing.T) {
+	tmpl := MustCompileTemplate(`SKU\-{A3}-{94}-aax\{L\}`)
+	g := NewGeneratorWithSeed(0)
+	for i := 0; i < 100; i++ {
+		b := tmpl.Generate(g)
+		if len(b) != tmpl.Len() {
+			t.Fatalf("%q: got len %d; want %d", b, len(b), tmpl.Len())
+		}
+		s := string(b)
+		if !strings.HasPrefix(s, "SKU-") || s[7] != '-' || s[12] != '-' || s[16] != '{' || s[18] != '}' {
+			t.Fatalf("%q: literals not preserved", s)
+		}
+		for _, check := range []struct {
+			lo, hi int
+			cs     Charset
+		}{
+			{4, 7, UpperAlphaCharset},
+			{8, 12, DigitsCharset},
+			{13, 15, LowerAlphaCharset},
+			{15, 16, AlphaNumCharset},
+			{17, 18, AlphaCharset},
+		} {
+			for _, c := range b[check.lo:check.hi] {
+				if !check.cs.Contains(c) {
+					t.Errorf("%q: %q not in %q", s, c, check.cs)
+				}
+			}
+		}
+	}
+	// the same seed produces the same output
+	g.Seed(0)
+	a := tmpl.Generate(g)
+	g.Seed(0)
+	if b := tmpl.Append(nil, g); string(a) != string(b) {
+		t.Errorf("got %q; want %q", b, a)
+	}
+	g.Seed(0)
+	if b, err := tmpl.GenerateErr(g); err != nil || string(a) != string(b) {
+		t.Errorf("got %q, %v; want %q, nil", b, err, a)
+	}
+	dst := make([]byte, 0, tmpl.Len())
+	allocs := testing.AllocsPerRun(100, func() { dst = tmpl.Append(dst[:0], g) })
+	if allocs != 0 {
+		t.Errorf("got %v allocs; want 0", allocs)
+	}
+}
+
+// errCharsReader is a CharsReader whose source of randomness always fails.
+type errCharsReader struct{}
+
+func (errCharsReader) FillChars(dst []byte, cs Charset) {
+	panic(ErrEntropy)
+}
+
+func (errCharsReader) ReadChars(dst []byte, cs Charset) error {
+	return ErrEntropy
+}
+
+func TestTemplateGenerateErr(t *testing.T) {
+	tmpl := MustCompileTemplate("AAA-999")
+	b, err := tmpl.GenerateErr(errCharsReader{})
+	if err != ErrEntropy {
+		t.Errorf("got %v; want %v", err, ErrEntropy)
+	}
+	if b != nil {
+		t.Errorf("got %q; want nil", b)
+	}
+	// literals alone don't use the generator
+	b, err = MustCompileTemplate(`\A-\9`).GenerateErr(errCharsReader{})
+	if err != nil || string(b) != "A-9" {
+		t.Errorf("got %q, %v; want %q, nil", b, err, "A-9")
+	}
+}
@@ -0,0 +1,72 @@
+package randchars
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidZ85 is returned when a string isn't valid Z85.
+var ErrInvalidZ85 = errors.New("randchars: invalid Z85")
+
+// z85Decode maps a Z85 character to its value plus 1; 0 is not a Z85
+// character.
+var z85Decode = func() (d [256]byte) {
+	for i := 0; i < len(z85); i++ {
+		d[z85[i]] = byte(i + 1)
+	}
+	return d
+}()
+
+// EncodeZ85 returns the Z85 encoding, https://rfc.zeromq.org/spec/32/, of b:
+// each 4 bytes, as a big-endian uint32, are encoded as 5 characters. Unlike
+// the ZeroMQ spec, b's length needn't be a multiple of 4: a final group of k
+// bytes is padded with zeros and only its first k+1 characters are kept, as
+// Ascii85 does, so the encoding has no padding and DecodeZ85 reverses it.
+func EncodeZ85(b []byte) []byte {
+	dst := make([]byte, 0, (len(b)*5+3)/4)
+	for len(b) > 0 {
+		var group [4]byte
+		k := copy(group[:], b)
+		b = b[k:]
+		v := uint32(group[0])<<24 | uint32(group[1])<<16 | uint32(group[2])<<8 | uint32(group[3])
+		var chars [5]byte
+		for i := 4; i >= 0; i-- {
+			chars[i] = z85[v%85]
+			v /= 85
+		}
+		dst = append(dst, chars[:k+1]...)
+	}
+	return dst
+}
+
+// DecodeZ85 returns the bytes encoded by s, as encoded by EncodeZ85. An error
+// wrapping ErrInvalidZ85 is returned if s has a character that isn't Z85, a
+// group that overflows 32 bits, or a final group of 1 character.
+func DecodeZ85(s string) ([]byte, error) {
+	if len(s)%5 == 1 {
+		return nil, fmt.Errorf("%w: %d: invalid length", ErrInvalidZ85, len(s))
+	}
+	dst := make([]byte, 0, len(s)*4/5)
+	for len(s) > 0 {
+		k := min(len(s), 5)
+		var v uint64
+		for i := 0; i < 5; i++ {
+			// a final partial group is padded with the highest value
+			d := byte(85)
+			if i < k {
+				d = z85Decode[s[i]]
+				if d == 0 {
+					return nil, fmt.Errorf("%w: %q: not a Z85 character", ErrInvalidZ85, s[i])
+				}
+			}
+			v = v*85 + uint64(d-1)
+		}
+		if v > 1<<32-1 {
+			return nil, fmt.Errorf("%w: %q: value out of range", ErrInvalidZ85, s[:k])
+		}
+		group := [4]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+		dst = append(dst, group[:k-1]...)
+		s = s[k:]
+	}
+	return dst, nil
+}
@@ -0,0 +1,86 @@
+package randchars
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestZ85(t *testing.T) {
+	tests := []struct {
+		b        []byte
+		expected string
+	}{
+		// from the ZeroMQ spec
+		{[]byte{0x86, 0x4f, 0xd2, 0x6f, 0xb5, 0x59, 0xf7, 0x5b}, "HelloWorld"},
+		{nil, ""},
+		{[]byte{0, 0, 0, 0}, "00000"},
+		{[]byte{0xff, 0xff, 0xff, 0xff}, "%nSc0"},
+		// partial groups keep k+1 characters
+		{[]byte{0x86}, "H5"},
+		{[]byte{0x86, 0x4f}, "Hed"},
+		{[]byte{0x86, 0x4f, 0xd2}, "Helj"},
+		{[]byte{0x86, 0x4f, 0xd2, 0x6f, 0xb5}, "HelloWe"},
+	}
+	for _, test := range tests {
+		s := string(EncodeZ85(test.b))
+		if s != test.expected {
+			t.Errorf("%x: got %q; want %q", test.b, s, test.expected)
+		}
+		b, err := DecodeZ85(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", s, err)
+			continue
+		}
+		if !bytes.Equal(b, test.b) {
+			t.Errorf("%q: got %x; want %x", s, b, test.b)
+		}
+	}
+	// every length round trips
+	g := NewGeneratorWithSeed(0)
+	for n := 0; n < 64; n++ {
+		b := make([]byte, n)
+		g.Read(b)
+		s := EncodeZ85(b)
+		if len(s) != (n*5+3)/4 {
+			t.Errorf("%d: got %d characters; want %d", n, len(s), (n*5+3)/4)
+		}
+		for _, c := range s {
+			if !Z85Charset.Contains(c) {
+				t.Errorf("%q: %q is not in %q", s, c, Z85Charset)
+			}
+		}
+		d, err := DecodeZ85(string(s))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %s", s, err)
+		}
+		if !bytes.Equal(d, b) {
+			t.Fatalf("%q: got %x; want %x", s, d, b)
+		}
+	}
+}
+
+func TestDecodeZ85Invalid(t *testing.T) {
+	tests := []struct {
+		s   string
+		err string
+	}{
+		{"H", "randchars: invalid Z85: 1: invalid length"},
+		{"Hello1", "randchars: invalid Z85: 6: invalid length"},
+		{"Hel o", "randchars: invalid Z85: ' ': not a Z85 character"},
+		{"#####", "randchars: invalid Z85: \"#####\": value out of range"},
+	}
+	for _, test := range tests {
+		_, err := DecodeZ85(test.s)
+		if err == nil {
+			t.Errorf("%q: got no error; want %q", test.s, test.err)
+			continue
+		}
+		if err.Error() != test.err {
+			t.Errorf("%q: got %q; want %q", test.s, err, test.err)
+		}
+		if !errors.Is(err, ErrInvalidZ85) {
+			t.Errorf("%q: got %v; want %v", test.s, err, ErrInvalidZ85)
+		}
+	}
+}